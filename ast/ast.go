@@ -2,6 +2,7 @@ package ast
 
 import (
 	"fmt"
+	"math/big"
 	"strconv"
 	"strings"
 )
@@ -13,72 +14,168 @@ type Expr interface {
 
 type (
 	BoolLit struct {
+		Span
 		Value bool
 	}
 
 	IntLit struct {
+		Span
 		Value int64
 	}
 
+	BigIntLit struct {
+		Span
+		Value *big.Int
+	}
+
+	RatLit struct {
+		Span
+		Value *big.Rat
+	}
+
+	FloatLit struct {
+		Span
+		Value float64
+	}
+
+	StringLit struct {
+		Span
+		Value string
+	}
+
+	CharLit struct {
+		Span
+		Value rune
+	}
+
+	// VectorLit is a vector literal, whose items are not evaluated.
+	VectorLit struct {
+		Span
+		List []Expr
+	}
+
+	BytevectorLit struct {
+		Span
+		Value []byte
+	}
+
 	Quote struct {
+		Span
 		Expr Expr
 	}
 
 	Ident struct {
+		Span
 		Name *string
 	}
 
 	ListExpr struct {
+		Span
 		List []Expr
 	}
 
+	// DottedList is an improper list (a b . c), which is only valid as a
+	// datum or as the parameters of a lambda.
+	DottedList struct {
+		Span
+		List []Expr
+		Tail Expr
+	}
+
 	DefineExpr struct {
+		Span
 		Ident *Ident
 		Value Expr
 	}
 
 	SetExpr struct {
+		Span
 		Ident *Ident
 		Value Expr
 	}
 
 	LambdaExpr struct {
-		Args []*Ident
-		Body []Expr
+		Span
+		Args     []*Ident
+		Optional []*Optional // parameters which may be omitted, after Args
+		Rest     *Ident      // parameter bound to the list of the extra arguments
+		Body     []Expr
+	}
+
+	// Optional is an optional parameter, which is Default if omitted, or
+	// false if Default is nil. Default is evaluated in the scope of the
+	// parameters before it.
+	Optional struct {
+		Ident   *Ident
+		Default Expr
+	}
+
+	// CaseLambdaExpr makes a procedure which calls the first clause taking
+	// the number of the arguments.
+	CaseLambdaExpr struct {
+		Span
+		List []*LambdaExpr
 	}
 
 	CondExpr struct {
+		Span
 		List []*BranchExpr
 	}
 
 	BranchExpr struct {
+		Span
 		Else      bool
 		Condition Expr
 		Body      []Expr
 	}
-)
 
-func (e *BoolLit) Pos() *Pos    { return nil }
-func (e *IntLit) Pos() *Pos     { return nil }
-func (e *Quote) Pos() *Pos      { return nil }
-func (e *Ident) Pos() *Pos      { return nil }
-func (e *ListExpr) Pos() *Pos   { return nil }
-func (e *DefineExpr) Pos() *Pos { return nil }
-func (e *SetExpr) Pos() *Pos    { return nil }
-func (e *LambdaExpr) Pos() *Pos { return nil }
-func (e *CondExpr) Pos() *Pos   { return nil }
-func (e *BranchExpr) Pos() *Pos { return nil }
-
-func (e *BoolLit) End() *Pos    { return nil }
-func (e *IntLit) End() *Pos     { return nil }
-func (e *Quote) End() *Pos      { return nil }
-func (e *Ident) End() *Pos      { return nil }
-func (e *ListExpr) End() *Pos   { return nil }
-func (e *DefineExpr) End() *Pos { return nil }
-func (e *SetExpr) End() *Pos    { return nil }
-func (e *LambdaExpr) End() *Pos { return nil }
-func (e *CondExpr) End() *Pos   { return nil }
-func (e *BranchExpr) End() *Pos { return nil }
+	// AndExpr gives the value of the first false expression, or of the last
+	// one if there is none.
+	AndExpr struct {
+		Span
+		List []Expr
+	}
+
+	// OrExpr gives the value of the first true expression, or of the last
+	// one if there is none.
+	OrExpr struct {
+		Span
+		List []Expr
+	}
+
+	// IfExpr evaluates Then if Condition is true, or Else otherwise. Then
+	// and Else may be nil, which evaluate to nothing.
+	IfExpr struct {
+		Span
+		Condition Expr
+		Then      Expr
+		Else      Expr
+	}
+
+	// BeginExpr evaluates its body in the enclosing scope, so that the
+	// definitions in it are visible after it.
+	BeginExpr struct {
+		Span
+		Body []Expr
+	}
+
+	CaseExpr struct {
+		Span
+		Key  Expr
+		List []*CaseClause
+	}
+
+	// CaseClause is selected if the key is eqv? to one of its data, which
+	// are not evaluated. With Arrow, its body is a single expression giving
+	// the procedure to call on the key.
+	CaseClause struct {
+		Span
+		Else  bool
+		Data  []Expr
+		Arrow bool
+		Body  []Expr
+	}
+)
 
 func NewIdent(name string) *Ident {
 	return &Ident{
@@ -94,6 +191,42 @@ func (e *IntLit) String() string {
 	return strconv.FormatInt(e.Value, 10)
 }
 
+func (e *BigIntLit) String() string {
+	return e.Value.String()
+}
+
+func (e *RatLit) String() string {
+	return e.Value.String()
+}
+
+func (e *FloatLit) String() string {
+	return strconv.FormatFloat(e.Value, 'g', -1, 64)
+}
+
+func (e *StringLit) String() string {
+	return strconv.Quote(e.Value)
+}
+
+func (e *CharLit) String() string {
+	return strconv.QuoteRune(e.Value)
+}
+
+func (e *VectorLit) String() string {
+	var substr []string
+	for _, expr := range e.List {
+		substr = append(substr, fmt.Sprintf("%s", expr))
+	}
+	return "#(" + strings.Join(substr, " ") + ")"
+}
+
+func (e *BytevectorLit) String() string {
+	var substr []string
+	for _, b := range e.Value {
+		substr = append(substr, strconv.Itoa(int(b)))
+	}
+	return "#u8(" + strings.Join(substr, " ") + ")"
+}
+
 func (e *Quote) String() string {
 	return fmt.Sprintf("'%s", e.Expr)
 }
@@ -110,6 +243,15 @@ func (e *ListExpr) String() string {
 	return "[" + strings.Join(substr, " ") + "]"
 }
 
+func (e *DottedList) String() string {
+	var substr []string
+	for _, expr := range e.List {
+		substr = append(substr, fmt.Sprintf("%s", expr))
+	}
+	substr = append(substr, ".", fmt.Sprintf("%s", e.Tail))
+	return "[" + strings.Join(substr, " ") + "]"
+}
+
 func (e *DefineExpr) String() string {
 	return fmt.Sprintf("(define %s %s)", e.Ident, e.Value)
 }
@@ -119,7 +261,35 @@ func (e *SetExpr) String() string {
 }
 
 func (e *LambdaExpr) String() string {
-	return fmt.Sprintf("(lambda %s %s)", e.Args, e.Body)
+	if len(e.Optional) == 0 && e.Rest == nil {
+		return fmt.Sprintf("(lambda %s %s)", e.Args, e.Body)
+	}
+	var substr []string
+	for _, arg := range e.Args {
+		substr = append(substr, *arg.Name)
+	}
+	if len(e.Optional) > 0 {
+		substr = append(substr, "#!optional")
+	}
+	for _, opt := range e.Optional {
+		if opt.Default == nil {
+			substr = append(substr, *opt.Ident.Name)
+		} else {
+			substr = append(substr, fmt.Sprintf("[%s %s]", opt.Ident, opt.Default))
+		}
+	}
+	if e.Rest != nil {
+		substr = append(substr, ".", *e.Rest.Name)
+	}
+	return fmt.Sprintf("(lambda [%s] %s)", strings.Join(substr, " "), e.Body)
+}
+
+func (e *CaseLambdaExpr) String() string {
+	substr := []string{"case-lambda"}
+	for i := range e.List {
+		substr = append(substr, fmt.Sprintf("%s", e.List[i]))
+	}
+	return "(" + strings.Join(substr, " ") + ")"
 }
 
 func (e *CondExpr) String() string {
@@ -142,3 +312,59 @@ func (e *BranchExpr) String() string {
 	}
 	return "(" + strings.Join(substr, " ") + ")"
 }
+
+func (e *AndExpr) String() string {
+	substr := []string{"and"}
+	for i := range e.List {
+		substr = append(substr, fmt.Sprintf("%s", e.List[i]))
+	}
+	return "(" + strings.Join(substr, " ") + ")"
+}
+
+func (e *OrExpr) String() string {
+	substr := []string{"or"}
+	for i := range e.List {
+		substr = append(substr, fmt.Sprintf("%s", e.List[i]))
+	}
+	return "(" + strings.Join(substr, " ") + ")"
+}
+
+func (e *IfExpr) String() string {
+	substr := []string{"if", fmt.Sprintf("%s", e.Condition), fmt.Sprint(e.Then)}
+	if e.Else != nil {
+		substr = append(substr, fmt.Sprintf("%s", e.Else))
+	}
+	return "(" + strings.Join(substr, " ") + ")"
+}
+
+func (e *BeginExpr) String() string {
+	substr := []string{"begin"}
+	for i := range e.Body {
+		substr = append(substr, fmt.Sprintf("%s", e.Body[i]))
+	}
+	return "(" + strings.Join(substr, " ") + ")"
+}
+
+func (e *CaseExpr) String() string {
+	substr := []string{"case", fmt.Sprintf("%s", e.Key)}
+	for i := range e.List {
+		substr = append(substr, fmt.Sprintf("%s", e.List[i]))
+	}
+	return "(" + strings.Join(substr, " ") + ")"
+}
+
+func (e *CaseClause) String() string {
+	var substr []string
+	if e.Else {
+		substr = append(substr, "else")
+	} else {
+		substr = append(substr, fmt.Sprintf("%s", e.Data))
+	}
+	if e.Arrow {
+		substr = append(substr, "=>")
+	}
+	for i := range e.Body {
+		substr = append(substr, fmt.Sprintf("%s", e.Body[i]))
+	}
+	return "(" + strings.Join(substr, " ") + ")"
+}
@@ -0,0 +1,68 @@
+package ast
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Phase tells which stage of the interpreter raised an error.
+type Phase int
+
+const (
+	ParsePhase Phase = iota
+	CompilePhase
+	RuntimePhase
+)
+
+var (
+	phaseString = map[Phase]string{
+		ParsePhase:   "parse",
+		CompilePhase: "compile",
+		RuntimePhase: "runtime",
+	}
+)
+
+func (p Phase) String() string {
+	if s, ok := phaseString[p]; ok {
+		return s
+	}
+	return "<unknown>"
+}
+
+// Error is an error located at the source range of an expression.
+type Error struct {
+	Phase Phase
+	From  *Pos
+	To    *Pos
+	Err   error
+}
+
+func NewError(phase Phase, expr Expr, err error) *Error {
+	return &Error{
+		Phase: phase,
+		From:  expr.Pos(),
+		To:    expr.End(),
+		Err:   err,
+	}
+}
+
+// WrapError locates err at expr, unless err has already been located at a
+// more specific expression.
+func WrapError(phase Phase, expr Expr, err error) error {
+	var e *Error
+	if errors.As(err, &e) && e.From != nil {
+		return err
+	}
+	return NewError(phase, expr, err)
+}
+
+func (e *Error) Error() string {
+	if e.From == nil {
+		return fmt.Sprintf("%s error: %s", e.Phase, e.Err)
+	}
+	return fmt.Sprintf("%s: %s error: %s", e.From, e.Phase, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
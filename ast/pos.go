@@ -1,8 +1,11 @@
 package ast
 
+import "fmt"
+
 type Pos struct {
-	Line   int
-	Column int
+	Filename string
+	Line     int
+	Column   int
 }
 
 func NewPos(line int, column int) Pos {
@@ -11,3 +14,31 @@ func NewPos(line int, column int) Pos {
 		Column: column,
 	}
 }
+
+func (p Pos) String() string {
+	if p.Filename == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.Filename, p.Line, p.Column)
+}
+
+// Span records the source range covered by an expression, from its first
+// character up to the position just after its last one.
+type Span struct {
+	From *Pos
+	To   *Pos
+}
+
+func (s *Span) Pos() *Pos { return s.From }
+func (s *Span) End() *Pos { return s.To }
+
+func (s *Span) setSpan(from, to *Pos) {
+	s.From, s.To = from, to
+}
+
+// SetSpan records the source range of expr.
+func SetSpan(expr Expr, from, to *Pos) {
+	if e, ok := expr.(interface{ setSpan(*Pos, *Pos) }); ok {
+		e.setSpan(from, to)
+	}
+}
@@ -16,38 +16,111 @@ func (t BuiltinTransformer) Transform(scope *ast.Scope, list *ast.ListExpr) (ast
 }
 
 var (
-	builtinDefine = BuiltinTransformer{name: "define", proc: defineSyntax}
-	builtinSet    = BuiltinTransformer{name: "set!", proc: setSyntax}
-	builtinLambda = BuiltinTransformer{name: "lambda", proc: lambdaSyntax}
-	builtinCond   = BuiltinTransformer{name: "cond", proc: condSyntax}
-	builtinQuote  = BuiltinTransformer{name: "quote", proc: quoteSyntax}
+	builtinDefine     = BuiltinTransformer{name: "define", proc: defineSyntax}
+	builtinSet        = BuiltinTransformer{name: "set!", proc: setSyntax}
+	builtinLambda     = BuiltinTransformer{name: "lambda", proc: lambdaSyntax}
+	builtinCond       = BuiltinTransformer{name: "cond", proc: condSyntax}
+	builtinQuote      = BuiltinTransformer{name: "quote", proc: quoteSyntax}
+	builtinLet        = BuiltinTransformer{name: "let", proc: letSyntax}
+	builtinLetStar    = BuiltinTransformer{name: "let*", proc: letStarSyntax}
+	builtinLetrec     = BuiltinTransformer{name: "letrec", proc: letrecSyntax}
+	builtinLetrecStar = BuiltinTransformer{name: "letrec*", proc: letrecSyntax}
+	builtinIf         = BuiltinTransformer{name: "if", proc: ifSyntax}
+	builtinWhen       = BuiltinTransformer{name: "when", proc: whenSyntax}
+	builtinUnless     = BuiltinTransformer{name: "unless", proc: whenSyntax}
+	builtinBegin      = BuiltinTransformer{name: "begin", proc: beginSyntax}
+	builtinCase       = BuiltinTransformer{name: "case", proc: caseSyntax}
+	builtinAnd        = BuiltinTransformer{name: "and", proc: andSyntax}
+	builtinOr         = BuiltinTransformer{name: "or", proc: orSyntax}
+	builtinCaseLambda = BuiltinTransformer{name: "case-lambda", proc: caseLambdaSyntax}
+
+	builtinQuasiquote      = BuiltinTransformer{name: "quasiquote", proc: quasiquoteSyntax}
+	builtinUnquote         = BuiltinTransformer{name: "unquote", proc: unquoteSyntax}
+	builtinUnquoteSplicing = BuiltinTransformer{name: "unquote-splicing", proc: unquoteSplicingSyntax}
+
+	builtinDefineSyntax = BuiltinTransformer{name: "define-syntax", proc: defineSyntaxSyntax}
+	builtinLetSyntax    = BuiltinTransformer{name: "let-syntax", proc: letSyntaxSyntax}
+	builtinLetrecSyntax = BuiltinTransformer{name: "letrec-syntax", proc: letSyntaxSyntax}
+	builtinSyntaxRules  = BuiltinTransformer{name: "syntax-rules", proc: syntaxRulesSyntax}
 )
 
 func builtinTransformerMap() map[string]Transformer {
 	return map[string]Transformer{
-		"define": builtinDefine,
-		"set!":   builtinSet,
-		"lambda": builtinLambda,
-		"cond":   builtinCond,
-		"quote":  builtinQuote,
+		"define":  builtinDefine,
+		"set!":    builtinSet,
+		"lambda":  builtinLambda,
+		"cond":    builtinCond,
+		"quote":   builtinQuote,
+		"let":     builtinLet,
+		"let*":    builtinLetStar,
+		"letrec":  builtinLetrec,
+		"letrec*": builtinLetrecStar,
+		"if":      builtinIf,
+		"when":    builtinWhen,
+		"unless":  builtinUnless,
+		"begin":   builtinBegin,
+		"case":    builtinCase,
+		"and":     builtinAnd,
+		"or":      builtinOr,
+
+		"case-lambda": builtinCaseLambda,
+
+		"quasiquote":       builtinQuasiquote,
+		"unquote":          builtinUnquote,
+		"unquote-splicing": builtinUnquoteSplicing,
+
+		"define-syntax": builtinDefineSyntax,
+		"let-syntax":    builtinLetSyntax,
+		"letrec-syntax": builtinLetrecSyntax,
+		"syntax-rules":  builtinSyntaxRules,
 	}
 }
 
 func defineSyntax(scope *ast.Scope, input *ast.ListExpr) (ast.Expr, error) {
 	badSyntaxErr := errors.New("define: bad syntax")
 	origList := input.List
-	if len(origList) != 3 {
+	if len(origList) < 3 {
 		return nil, badSyntaxErr
 	}
+
+	// the shorthand (define (f . formals) body ...) defines f as
+	// (lambda formals body ...), and f may be a shorthand itself for curried
+	// procedures
+	target, body := origList[1], origList[2:]
+	for {
+		var params ast.Expr
+		switch list := target.(type) {
+		case *ast.ListExpr:
+			if len(list.List) == 0 {
+				return nil, badSyntaxErr
+			}
+			target, params = list.List[0], &ast.ListExpr{Span: list.Span, List: list.List[1:]}
+		case *ast.DottedList:
+			target, params = list.List[0], list.Tail
+			if len(list.List) > 1 {
+				params = &ast.DottedList{Span: list.Span, List: list.List[1:], Tail: list.Tail}
+			}
+		}
+		if params == nil {
+			break
+		}
+		lambda, ok := formals(params)
+		if !ok {
+			return nil, badSyntaxErr
+		}
+		lambda.Span, lambda.Body = input.Span, body
+		body = []ast.Expr{lambda}
+	}
 	// ensure an identifier is given
-	ident, ok := origList[1].(*ast.Ident)
-	if !ok {
+	ident, ok := target.(*ast.Ident)
+	if !ok || len(body) != 1 {
 		return nil, badSyntaxErr
 	}
 
 	return &ast.DefineExpr{
+		Span:  input.Span,
 		Ident: ident,
-		Value: origList[2],
+		Value: body[0],
 	}, nil
 }
 
@@ -64,6 +137,7 @@ func setSyntax(scope *ast.Scope, input *ast.ListExpr) (ast.Expr, error) {
 	}
 
 	return &ast.SetExpr{
+		Span:  input.Span,
 		Ident: ident,
 		Value: origList[2],
 	}, nil
@@ -76,24 +150,125 @@ func lambdaSyntax(scope *ast.Scope, input *ast.ListExpr) (ast.Expr, error) {
 		return nil, badSyntaxErr
 	}
 
-	// ensure the argument list consists of identifiers
-	argList, ok := origList[1].(*ast.ListExpr)
+	lambda, ok := formals(origList[1])
 	if !ok {
 		return nil, badSyntaxErr
 	}
-	var args []*ast.Ident
-	for _, arg := range argList.List {
-		ident, ok := arg.(*ast.Ident)
+	lambda.Span, lambda.Body = input.Span, origList[2:]
+	return lambda, nil
+}
+
+// formals parses the parameters of a lambda. They are either an identifier
+// bound to the list of all the arguments, or a list of the required
+// parameters, followed by the optional ones after '#!optional', and by the
+// rest one after '#!rest' or as the tail of an improper list. An optional
+// parameter is an identifier, or a list of an identifier and its default
+// value.
+func formals(expr ast.Expr) (*ast.LambdaExpr, bool) {
+	var items []ast.Expr
+	var tail ast.Expr
+	switch list := expr.(type) {
+	case *ast.Ident:
+		return &ast.LambdaExpr{Rest: list}, true
+	case *ast.ListExpr:
+		items = list.List
+	case *ast.DottedList:
+		items, tail = list.List, list.Tail
+	default:
+		return nil, false
+	}
+
+	const (
+		required = iota
+		optional
+		rest
+		end
+	)
+	lambda := &ast.LambdaExpr{}
+	mode := required
+	for _, item := range items {
+		ident, isIdent := item.(*ast.Ident)
+		if isIdent {
+			switch *ident.Name {
+			case "#!optional":
+				if mode != required {
+					return nil, false
+				}
+				mode = optional
+				continue
+			case "#!rest":
+				if mode > optional {
+					return nil, false
+				}
+				mode = rest
+				continue
+			}
+		}
+
+		switch mode {
+		case required:
+			if !isIdent {
+				return nil, false
+			}
+			lambda.Args = append(lambda.Args, ident)
+		case optional:
+			if isIdent {
+				lambda.Optional = append(lambda.Optional, &ast.Optional{Ident: ident})
+				continue
+			}
+			pair, ok := item.(*ast.ListExpr)
+			if !ok || len(pair.List) != 2 {
+				return nil, false
+			}
+			ident, ok := pair.List[0].(*ast.Ident)
+			if !ok {
+				return nil, false
+			}
+			lambda.Optional = append(lambda.Optional, &ast.Optional{Ident: ident, Default: pair.List[1]})
+		case rest:
+			if !isIdent {
+				return nil, false
+			}
+			lambda.Rest = ident
+			mode = end
+		default:
+			return nil, false
+		}
+	}
+	if tail != nil {
+		ident, ok := tail.(*ast.Ident)
+		if !ok || mode > optional {
+			return nil, false
+		}
+		lambda.Rest, mode = ident, end
+	}
+	// a rest parameter must follow '#!rest'
+	return lambda, mode != rest
+}
+
+func caseLambdaSyntax(scope *ast.Scope, input *ast.ListExpr) (ast.Expr, error) {
+	badSyntaxErr := errors.New("case-lambda: bad syntax")
+	origList := input.List
+	if len(origList) < 2 {
+		return nil, badSyntaxErr
+	}
+
+	var lambdaList []*ast.LambdaExpr
+	for _, clause := range origList[1:] {
+		// ensure each clause is a list of the parameters followed by at
+		// least 1 expression
+		list, ok := clause.(*ast.ListExpr)
+		if !ok || len(list.List) < 2 {
+			return nil, badSyntaxErr
+		}
+		lambda, ok := formals(list.List[0])
 		if !ok {
 			return nil, badSyntaxErr
 		}
-		args = append(args, ident)
+		lambda.Span, lambda.Body = list.Span, list.List[1:]
+		lambdaList = append(lambdaList, lambda)
 	}
-
-	return &ast.LambdaExpr{
-		Args: args,
-		Body: origList[2:],
-	}, nil
+	return &ast.CaseLambdaExpr{Span: input.Span, List: lambdaList}, nil
 }
 
 func condSyntax(scope *ast.Scope, input *ast.ListExpr) (ast.Expr, error) {
@@ -129,12 +304,14 @@ func condSyntax(scope *ast.Scope, input *ast.ListExpr) (ast.Expr, error) {
 		}
 
 		branchList = append(branchList, &ast.BranchExpr{
+			Span:      list.Span,
 			Else:      elseBranch,
 			Condition: condition,
 			Body:      list.List[1:],
 		})
 	}
 	return &ast.CondExpr{
+		Span: input.Span,
 		List: branchList,
 	}, nil
 }
@@ -145,5 +322,238 @@ func quoteSyntax(scope *ast.Scope, input *ast.ListExpr) (ast.Expr, error) {
 	if len(origList) != 2 {
 		return nil, badSyntaxErr
 	}
-	return &ast.Quote{Expr: origList[1]}, nil
+	return &ast.Quote{Span: input.Span, Expr: origList[1]}, nil
+}
+
+// bindings splits the binding list of a let form into the variables and their
+// initial values.
+func bindings(expr ast.Expr) ([]*ast.Ident, []ast.Expr, bool) {
+	list, ok := expr.(*ast.ListExpr)
+	if !ok {
+		return nil, nil, false
+	}
+	vars := []*ast.Ident{}
+	var inits []ast.Expr
+	for _, item := range list.List {
+		binding, ok := item.(*ast.ListExpr)
+		if !ok || len(binding.List) != 2 {
+			return nil, nil, false
+		}
+		ident, ok := binding.List[0].(*ast.Ident)
+		if !ok {
+			return nil, nil, false
+		}
+		vars = append(vars, ident)
+		inits = append(inits, binding.List[1])
+	}
+	return vars, inits, true
+}
+
+// letSyntax lowers (let ((v init) ...) body ...) to an application of a
+// lambda: ((lambda (v ...) body ...) init ...).
+// A named let (let name ((v init) ...) body ...) binds name to the lambda
+// within its own body, so that the body can loop by calling it in tail
+// position:
+// (((lambda () (define name (lambda (v ...) body ...)) name)) init ...).
+func letSyntax(scope *ast.Scope, input *ast.ListExpr) (ast.Expr, error) {
+	badSyntaxErr := errors.New("let: bad syntax")
+	origList := input.List
+	if len(origList) < 3 {
+		return nil, badSyntaxErr
+	}
+
+	name, named := origList[1].(*ast.Ident)
+	if named {
+		origList = origList[1:]
+		if len(origList) < 3 {
+			return nil, badSyntaxErr
+		}
+	}
+	vars, inits, ok := bindings(origList[1])
+	if !ok {
+		return nil, badSyntaxErr
+	}
+
+	var operator ast.Expr = &ast.LambdaExpr{
+		Span: input.Span,
+		Args: vars,
+		Body: origList[2:],
+	}
+	if named {
+		operator = &ast.ListExpr{
+			Span: input.Span,
+			List: []ast.Expr{&ast.LambdaExpr{
+				Span: input.Span,
+				Args: []*ast.Ident{},
+				Body: []ast.Expr{
+					&ast.DefineExpr{Span: input.Span, Ident: name, Value: operator},
+					name,
+				},
+			}},
+		}
+	}
+	return &ast.ListExpr{
+		Span: input.Span,
+		List: append([]ast.Expr{operator}, inits...),
+	}, nil
+}
+
+// letStarSyntax lowers (let* ((v1 init1) (v2 init2) ...) body ...) to nested
+// lets: ((lambda (v1) (let* ((v2 init2) ...) body ...)) init1).
+func letStarSyntax(scope *ast.Scope, input *ast.ListExpr) (ast.Expr, error) {
+	badSyntaxErr := errors.New("let*: bad syntax")
+	origList := input.List
+	if len(origList) < 3 {
+		return nil, badSyntaxErr
+	}
+	vars, inits, ok := bindings(origList[1])
+	if !ok {
+		return nil, badSyntaxErr
+	}
+
+	body := origList[2:]
+	if len(vars) > 1 {
+		rest := origList[1].(*ast.ListExpr)
+		body = []ast.Expr{&ast.ListExpr{
+			Span: input.Span,
+			List: append([]ast.Expr{
+				origList[0],
+				&ast.ListExpr{Span: rest.Span, List: rest.List[1:]},
+			}, body...),
+		}}
+		vars, inits = vars[:1], inits[:1]
+	}
+	return &ast.ListExpr{
+		Span: input.Span,
+		List: append([]ast.Expr{&ast.LambdaExpr{
+			Span: input.Span,
+			Args: vars,
+			Body: body,
+		}}, inits...),
+	}, nil
+}
+
+// letrecSyntax lowers (letrec ((v init) ...) body ...) to the internal
+// definitions of a lambda: ((lambda () (define v init) ... body ...)).
+// The initial values are evaluated from left to right, so it serves letrec*
+// as well.
+func letrecSyntax(scope *ast.Scope, input *ast.ListExpr) (ast.Expr, error) {
+	name := *input.List[0].(*ast.Ident).Name
+	badSyntaxErr := errors.New(name + ": bad syntax")
+	origList := input.List
+	if len(origList) < 3 {
+		return nil, badSyntaxErr
+	}
+	vars, inits, ok := bindings(origList[1])
+	if !ok {
+		return nil, badSyntaxErr
+	}
+
+	var body []ast.Expr
+	for i := range vars {
+		body = append(body, &ast.DefineExpr{Span: input.Span, Ident: vars[i], Value: inits[i]})
+	}
+	body = append(body, origList[2:]...)
+	return &ast.ListExpr{
+		Span: input.Span,
+		List: []ast.Expr{&ast.LambdaExpr{
+			Span: input.Span,
+			Args: []*ast.Ident{},
+			Body: body,
+		}},
+	}, nil
+}
+
+func ifSyntax(scope *ast.Scope, input *ast.ListExpr) (ast.Expr, error) {
+	badSyntaxErr := errors.New("if: bad syntax")
+	origList := input.List
+	if len(origList) != 3 && len(origList) != 4 {
+		return nil, badSyntaxErr
+	}
+	ifExpr := &ast.IfExpr{
+		Span:      input.Span,
+		Condition: origList[1],
+		Then:      origList[2],
+	}
+	if len(origList) == 4 {
+		ifExpr.Else = origList[3]
+	}
+	return ifExpr, nil
+}
+
+// whenSyntax transforms (when test body ...) to (if test (begin body ...)),
+// and (unless test body ...) to the if expression with the body as its else
+// branch.
+func whenSyntax(scope *ast.Scope, input *ast.ListExpr) (ast.Expr, error) {
+	name := *input.List[0].(*ast.Ident).Name
+	badSyntaxErr := errors.New(name + ": bad syntax")
+	origList := input.List
+	if len(origList) < 3 {
+		return nil, badSyntaxErr
+	}
+	body := &ast.BeginExpr{Span: input.Span, Body: origList[2:]}
+	if name == "unless" {
+		return &ast.IfExpr{Span: input.Span, Condition: origList[1], Else: body}, nil
+	}
+	return &ast.IfExpr{Span: input.Span, Condition: origList[1], Then: body}, nil
+}
+
+func beginSyntax(scope *ast.Scope, input *ast.ListExpr) (ast.Expr, error) {
+	return &ast.BeginExpr{Span: input.Span, Body: input.List[1:]}, nil
+}
+
+func caseSyntax(scope *ast.Scope, input *ast.ListExpr) (ast.Expr, error) {
+	badSyntaxErr := errors.New("case: bad syntax")
+	elseSyntaxErr := errors.New("case: bad syntax: 'else' clause must be last")
+	origList := input.List
+	if len(origList) < 2 {
+		return nil, badSyntaxErr
+	}
+
+	var clauseList []*ast.CaseClause
+	var last bool
+	for _, clause := range origList[2:] {
+		if last {
+			return nil, elseSyntaxErr
+		}
+		// ensure each clause is a list of the data or 'else', followed by
+		// at least 1 expression
+		list, ok := clause.(*ast.ListExpr)
+		if !ok || len(list.List) < 2 {
+			return nil, badSyntaxErr
+		}
+
+		caseClause := &ast.CaseClause{Span: list.Span, Body: list.List[1:]}
+		switch head := list.List[0].(type) {
+		case *ast.Ident:
+			if *head.Name != "else" {
+				return nil, badSyntaxErr
+			}
+			last, caseClause.Else = true, true
+		case *ast.ListExpr:
+			caseClause.Data = head.List
+		default:
+			return nil, badSyntaxErr
+		}
+		if arrow, ok := list.List[1].(*ast.Ident); ok && *arrow.Name == "=>" {
+			if len(list.List) != 3 {
+				return nil, badSyntaxErr
+			}
+			caseClause.Arrow, caseClause.Body = true, list.List[2:]
+		}
+		clauseList = append(clauseList, caseClause)
+	}
+	return &ast.CaseExpr{
+		Span: input.Span,
+		Key:  origList[1],
+		List: clauseList,
+	}, nil
+}
+
+func andSyntax(scope *ast.Scope, input *ast.ListExpr) (ast.Expr, error) {
+	return &ast.AndExpr{Span: input.Span, List: input.List[1:]}, nil
+}
+
+func orSyntax(scope *ast.Scope, input *ast.ListExpr) (ast.Expr, error) {
+	return &ast.OrExpr{Span: input.Span, List: input.List[1:]}, nil
 }
@@ -120,6 +120,26 @@ func Test_transform(t *testing.T) {
 		},
 	}
 
+	from, to := ast.NewPos(1, 1), ast.NewPos(1, 13)
+	testData = append(testData, struct {
+		input  ast.Expr
+		result ast.Expr
+	}{
+		input: &ast.ListExpr{
+			Span: ast.Span{From: &from, To: &to},
+			List: []ast.Expr{
+				ast.NewIdent("define"),
+				ast.NewIdent("x"),
+				&ast.IntLit{Value: 1},
+			},
+		},
+		result: &ast.DefineExpr{
+			Span:  ast.Span{From: &from, To: &to},
+			Ident: ast.NewIdent("x"),
+			Value: &ast.IntLit{Value: 1},
+		},
+	})
+
 	scope := ast.NewRootScope()
 	for k, v := range builtinTransformerMap() {
 		scope.Insert(ast.SymbolMap(k), v)
@@ -137,3 +157,258 @@ func Test_transform(t *testing.T) {
 		}
 	}
 }
+
+func Test_transformLet(t *testing.T) {
+	x, y, loop := ast.NewIdent("x"), ast.NewIdent("y"), ast.NewIdent("loop")
+	list := func(items ...ast.Expr) *ast.ListExpr {
+		return &ast.ListExpr{List: items}
+	}
+	one, two := &ast.IntLit{Value: 1}, &ast.IntLit{Value: 2}
+
+	testData := []struct {
+		input  ast.Expr
+		result ast.Expr
+	}{
+		{
+			input: list(ast.NewIdent("let"), list(list(x, one), list(y, two)), x),
+			result: list(
+				&ast.LambdaExpr{Args: []*ast.Ident{x, y}, Body: []ast.Expr{x}},
+				one, two),
+		},
+		{
+			input: list(ast.NewIdent("let"), loop, list(list(x, one)), list(loop, x)),
+			result: list(
+				list(&ast.LambdaExpr{Args: []*ast.Ident{}, Body: []ast.Expr{
+					&ast.DefineExpr{Ident: loop, Value: &ast.LambdaExpr{
+						Args: []*ast.Ident{x},
+						Body: []ast.Expr{list(loop, x)},
+					}},
+					loop,
+				}}),
+				one),
+		},
+		{
+			input: list(ast.NewIdent("let*"), list(list(x, one), list(y, x)), y),
+			result: list(
+				&ast.LambdaExpr{Args: []*ast.Ident{x}, Body: []ast.Expr{
+					list(&ast.LambdaExpr{Args: []*ast.Ident{y}, Body: []ast.Expr{y}}, x),
+				}},
+				one),
+		},
+		{
+			input: list(ast.NewIdent("letrec"), list(list(x, one)), x),
+			result: list(&ast.LambdaExpr{Args: []*ast.Ident{}, Body: []ast.Expr{
+				&ast.DefineExpr{Ident: x, Value: one},
+				x,
+			}}),
+		},
+	}
+
+	scope := ast.NewRootScope()
+	for k, v := range builtinTransformerMap() {
+		scope.Insert(ast.SymbolMap(k), v)
+	}
+	for _, test := range testData {
+		result, err := transform(scope, test.input)
+		if err != nil {
+			t.Error(err)
+			break
+		}
+		if !reflect.DeepEqual(result, test.result) {
+			t.Errorf("\ninput: '%s'\nexpect: %s\noutput: %s", test.input, test.result, result)
+		}
+	}
+
+	badData := []ast.Expr{
+		list(ast.NewIdent("let"), list(list(x)), x),
+		list(ast.NewIdent("let"), list(list(one, two)), x),
+		list(ast.NewIdent("let"), loop, list()),
+		list(ast.NewIdent("let*"), x, x),
+		list(ast.NewIdent("letrec"), list(list(x, one))),
+	}
+	for _, input := range badData {
+		if _, err := transform(scope, input); err == nil {
+			t.Errorf("expect an error for '%s'", input)
+		}
+	}
+}
+
+func Test_transformIfCase(t *testing.T) {
+	x, y := ast.NewIdent("x"), ast.NewIdent("y")
+	list := func(items ...ast.Expr) *ast.ListExpr {
+		return &ast.ListExpr{List: items}
+	}
+	one, two := &ast.IntLit{Value: 1}, &ast.IntLit{Value: 2}
+
+	testData := []struct {
+		input  ast.Expr
+		result ast.Expr
+	}{
+		{
+			input:  list(ast.NewIdent("if"), x, one),
+			result: &ast.IfExpr{Condition: x, Then: one},
+		},
+		{
+			input:  list(ast.NewIdent("if"), x, one, list(ast.NewIdent("if"), y, two)),
+			result: &ast.IfExpr{Condition: x, Then: one, Else: &ast.IfExpr{Condition: y, Then: two}},
+		},
+		{
+			input:  list(ast.NewIdent("and"), x, list(ast.NewIdent("or"), y, one)),
+			result: &ast.AndExpr{List: []ast.Expr{x, &ast.OrExpr{List: []ast.Expr{y, one}}}},
+		},
+		{
+			input:  list(ast.NewIdent("or")),
+			result: &ast.OrExpr{},
+		},
+		{
+			input:  list(ast.NewIdent("when"), x, one, two),
+			result: &ast.IfExpr{Condition: x, Then: &ast.BeginExpr{Body: []ast.Expr{one, two}}},
+		},
+		{
+			input:  list(ast.NewIdent("unless"), x, one),
+			result: &ast.IfExpr{Condition: x, Else: &ast.BeginExpr{Body: []ast.Expr{one}}},
+		},
+		{
+			input: list(ast.NewIdent("begin"), list(ast.NewIdent("define"), x, one), x),
+			result: &ast.BeginExpr{Body: []ast.Expr{
+				&ast.DefineExpr{Ident: x, Value: one},
+				x,
+			}},
+		},
+		{
+			input: list(ast.NewIdent("case"), x,
+				list(list(one, two), y),
+				list(ast.NewIdent("else"), ast.NewIdent("=>"), y)),
+			result: &ast.CaseExpr{Key: x, List: []*ast.CaseClause{
+				{Data: []ast.Expr{one, two}, Body: []ast.Expr{y}},
+				{Else: true, Arrow: true, Body: []ast.Expr{y}},
+			}},
+		},
+	}
+
+	scope := ast.NewRootScope()
+	for k, v := range builtinTransformerMap() {
+		scope.Insert(ast.SymbolMap(k), v)
+	}
+	for _, test := range testData {
+		result, err := transform(scope, test.input)
+		if err != nil {
+			t.Error(err)
+			break
+		}
+		if !reflect.DeepEqual(result, test.result) {
+			t.Errorf("\ninput: '%s'\nexpect: %s\noutput: %s", test.input, test.result, result)
+		}
+	}
+
+	badData := []ast.Expr{
+		list(ast.NewIdent("if"), x),
+		list(ast.NewIdent("if"), x, one, two, y),
+		list(ast.NewIdent("when"), x),
+		list(ast.NewIdent("case")),
+		list(ast.NewIdent("case"), x, list(ast.NewIdent("else"), one), list(list(one), two)),
+		list(ast.NewIdent("case"), x, list(list(one))),
+		list(ast.NewIdent("case"), x, list(list(one), ast.NewIdent("=>"), y, y)),
+		list(ast.NewIdent("case"), x, list(y, one)),
+	}
+	for _, input := range badData {
+		if _, err := transform(scope, input); err == nil {
+			t.Errorf("expect an error for '%s'", input)
+		}
+	}
+}
+
+func Test_transformParams(t *testing.T) {
+	f, x, y, rest := ast.NewIdent("f"), ast.NewIdent("x"), ast.NewIdent("y"), ast.NewIdent("rest")
+	optional := ast.NewIdent("#!optional")
+	dotted := func(tail ast.Expr, items ...ast.Expr) *ast.DottedList {
+		return &ast.DottedList{List: items, Tail: tail}
+	}
+	list := func(items ...ast.Expr) *ast.ListExpr {
+		return &ast.ListExpr{List: items}
+	}
+	one := &ast.IntLit{Value: 1}
+
+	testData := []struct {
+		input  ast.Expr
+		result ast.Expr
+	}{
+		{
+			input: list(ast.NewIdent("define"), list(f, x), x),
+			result: &ast.DefineExpr{Ident: f, Value: &ast.LambdaExpr{
+				Args: []*ast.Ident{x},
+				Body: []ast.Expr{x},
+			}},
+		},
+		{
+			input: list(ast.NewIdent("define"), list(list(f, x), y), x),
+			result: &ast.DefineExpr{Ident: f, Value: &ast.LambdaExpr{
+				Args: []*ast.Ident{x},
+				Body: []ast.Expr{&ast.LambdaExpr{Args: []*ast.Ident{y}, Body: []ast.Expr{x}}},
+			}},
+		},
+		{
+			input: list(ast.NewIdent("define"), dotted(rest, f, x), rest),
+			result: &ast.DefineExpr{Ident: f, Value: &ast.LambdaExpr{
+				Args: []*ast.Ident{x},
+				Rest: rest,
+				Body: []ast.Expr{rest},
+			}},
+		},
+		{
+			input:  list(ast.NewIdent("lambda"), rest, rest),
+			result: &ast.LambdaExpr{Rest: rest, Body: []ast.Expr{rest}},
+		},
+		{
+			input: list(ast.NewIdent("lambda"), list(x, optional, y, list(f, one), ast.NewIdent("#!rest"), rest), x),
+			result: &ast.LambdaExpr{
+				Args:     []*ast.Ident{x},
+				Optional: []*ast.Optional{{Ident: y}, {Ident: f, Default: one}},
+				Rest:     rest,
+				Body:     []ast.Expr{x},
+			},
+		},
+		{
+			input: list(ast.NewIdent("case-lambda"), list(list(x), x), list(rest, rest)),
+			result: &ast.CaseLambdaExpr{List: []*ast.LambdaExpr{
+				{Args: []*ast.Ident{x}, Body: []ast.Expr{x}},
+				{Rest: rest, Body: []ast.Expr{rest}},
+			}},
+		},
+	}
+
+	scope := ast.NewRootScope()
+	for k, v := range builtinTransformerMap() {
+		scope.Insert(ast.SymbolMap(k), v)
+	}
+	for _, test := range testData {
+		result, err := transform(scope, test.input)
+		if err != nil {
+			t.Error(err)
+			break
+		}
+		if !reflect.DeepEqual(result, test.result) {
+			t.Errorf("\ninput: '%s'\nexpect: %s\noutput: %s", test.input, test.result, result)
+		}
+	}
+
+	badData := []ast.Expr{
+		list(ast.NewIdent("define"), list(f, x)),
+		list(ast.NewIdent("define"), list(), x),
+		list(ast.NewIdent("define"), x, one, one),
+		list(ast.NewIdent("lambda"), dotted(one, x), x),
+		list(ast.NewIdent("lambda"), dotted(rest, x, ast.NewIdent("#!rest"), y), x),
+		list(ast.NewIdent("lambda"), list(x, ast.NewIdent("#!rest")), x),
+		list(ast.NewIdent("define"), dotted(one, f), x),
+		dotted(x, f),
+		list(ast.NewIdent("lambda"), list(optional, list(x)), x),
+		list(ast.NewIdent("lambda"), list(one), x),
+		list(ast.NewIdent("case-lambda")),
+		list(ast.NewIdent("case-lambda"), list(list(x))),
+	}
+	for _, input := range badData {
+		if _, err := transform(scope, input); err == nil {
+			t.Errorf("expect an error for '%s'", input)
+		}
+	}
+}
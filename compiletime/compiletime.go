@@ -1,6 +1,7 @@
 package compiletime
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/dyzsr/mylisp/ast"
@@ -24,35 +25,55 @@ func (c *CompileTime) Eval(input ast.Expr) (ast.Expr, error) {
 	return transform(c.scope, input)
 }
 
+// maxExpansions bounds the nesting of the expansions being transformed, so
+// that a macro which expands into itself forever is an error rather than a
+// stack overflow.
+const maxExpansions = 10000
+
+// expansions counts the nested expansions being transformed.
+var expansions int
+
 func transform(scope *ast.Scope, input ast.Expr) (ast.Expr, error) {
-	if ident, ok := input.(*ast.Ident); ok {
-		if _, ok := scope.Lookup(ident.Name); ok {
-			return nil, fmt.Errorf("%s: bad syntax", *ident.Name)
+	switch expr := input.(type) {
+	case *ast.Ident:
+		if _, ok := scope.Lookup(expr.Name); ok {
+			return nil, ast.NewError(ast.CompilePhase, expr, fmt.Errorf("%s: bad syntax", *expr.Name))
 		}
+	case *ast.DottedList:
+		// an improper list is only valid as a datum
+		return nil, ast.NewError(ast.CompilePhase, expr, errors.New("bad syntax: improper list"))
 	}
-	// skip non-list expression:
-	// either atomic or has already been transformed
-	origList, ok := input.(*ast.ListExpr)
-	if !ok {
-		return input, nil
-	}
-	if len(origList.List) == 0 {
-		return input, nil
-	}
-
-	// apply transformers
+	// apply transformers to list expressions; the other expressions are
+	// either atomic or have already been transformed, such as those built by
+	// transformers, whose sub-expressions may still need transforming
 	var intermediate ast.Expr = input
-	first := origList.List[0]
-	if ident, ok := first.(*ast.Ident); ok {
-		if value, ok := scope.Lookup(ident.Name); ok {
-			if transformer, ok := value.(Transformer); ok {
-				var err error
-				intermediate, err = transformer.Transform(scope, origList)
-				if err != nil {
-					return nil, err
+	if origList, ok := input.(*ast.ListExpr); ok {
+		if len(origList.List) == 0 {
+			return input, nil
+		}
+		first := origList.List[0]
+		if ident, ok := first.(*ast.Ident); ok {
+			if value, ok := scope.Lookup(ident.Name); ok {
+				if transformer, ok := value.(Transformer); ok {
+					if expansions >= maxExpansions {
+						err := fmt.Errorf("%s: too many nested expansions", *ident.Name)
+						return nil, ast.NewError(ast.CompilePhase, origList, err)
+					}
+					expansions++
+					defer func() { expansions-- }()
+					var err error
+					intermediate, err = transformer.Transform(scope, origList)
+					if err != nil {
+						return nil, ast.WrapError(ast.CompilePhase, origList, err)
+					}
+					// a list expression in the output may be another form
+					// to expand
+					if list, ok := intermediate.(*ast.ListExpr); ok {
+						return transform(scope, list)
+					}
+				} else {
+					panic("invalid tranformer type")
 				}
-			} else {
-				panic("invalid tranformer type")
 			}
 		}
 	}
@@ -69,32 +90,59 @@ func transform(scope *ast.Scope, input ast.Expr) (ast.Expr, error) {
 			}
 			list = append(list, expr)
 		}
-		return &ast.ListExpr{List: list}, nil
+		return &ast.ListExpr{Span: expr.Span, List: list}, nil
 
 	case *ast.DefineExpr:
 		value, err := transform(scope, expr.Value)
 		if err != nil {
 			return nil, err
 		}
-		return &ast.DefineExpr{Ident: expr.Ident, Value: value}, nil
+		return &ast.DefineExpr{Span: expr.Span, Ident: expr.Ident, Value: value}, nil
 
 	case *ast.SetExpr:
 		value, err := transform(scope, expr.Value)
 		if err != nil {
 			return nil, err
 		}
-		return &ast.SetExpr{Ident: expr.Ident, Value: value}, nil
+		return &ast.SetExpr{Span: expr.Span, Ident: expr.Ident, Value: value}, nil
 
 	case *ast.LambdaExpr:
+		var optional []*ast.Optional
+		for _, opt := range expr.Optional {
+			value, err := transform(scope, opt.Default)
+			if err != nil {
+				return nil, err
+			}
+			optional = append(optional, &ast.Optional{Ident: opt.Ident, Default: value})
+		}
+		// the syntax definitions in the body are local to it
+		inner := ast.NewScope(scope)
 		var body []ast.Expr
 		for i := range expr.Body {
-			result, err := transform(scope, expr.Body[i])
+			result, err := transform(inner, expr.Body[i])
 			if err != nil {
 				return nil, err
 			}
 			body = append(body, result)
 		}
-		return &ast.LambdaExpr{Args: expr.Args, Body: body}, nil
+		return &ast.LambdaExpr{
+			Span:     expr.Span,
+			Args:     expr.Args,
+			Optional: optional,
+			Rest:     expr.Rest,
+			Body:     body,
+		}, nil
+
+	case *ast.CaseLambdaExpr:
+		var lambdaList []*ast.LambdaExpr
+		for _, lambda := range expr.List {
+			result, err := transform(scope, lambda)
+			if err != nil {
+				return nil, err
+			}
+			lambdaList = append(lambdaList, result.(*ast.LambdaExpr))
+		}
+		return &ast.CaseLambdaExpr{Span: expr.Span, List: lambdaList}, nil
 
 	case *ast.CondExpr:
 		var branchList []*ast.BranchExpr
@@ -118,12 +166,68 @@ func transform(scope *ast.Scope, input ast.Expr) (ast.Expr, error) {
 			}
 
 			branchList = append(branchList, &ast.BranchExpr{
+				Span:      branch.Span,
 				Else:      branch.Else,
 				Condition: condition,
 				Body:      body,
 			})
 		}
-		return &ast.CondExpr{List: branchList}, nil
+		return &ast.CondExpr{Span: expr.Span, List: branchList}, nil
+
+	case *ast.IfExpr:
+		var branches [3]ast.Expr
+		for i, branch := range []ast.Expr{expr.Condition, expr.Then, expr.Else} {
+			if branch == nil {
+				continue
+			}
+			var err error
+			if branches[i], err = transform(scope, branch); err != nil {
+				return nil, err
+			}
+		}
+		return &ast.IfExpr{Span: expr.Span, Condition: branches[0], Then: branches[1], Else: branches[2]}, nil
+
+	case *ast.AndExpr:
+		list, err := transformList(scope, expr.List)
+		if err != nil {
+			return nil, err
+		}
+		return &ast.AndExpr{Span: expr.Span, List: list}, nil
+
+	case *ast.OrExpr:
+		list, err := transformList(scope, expr.List)
+		if err != nil {
+			return nil, err
+		}
+		return &ast.OrExpr{Span: expr.Span, List: list}, nil
+
+	case *ast.BeginExpr:
+		body, err := transformList(scope, expr.Body)
+		if err != nil {
+			return nil, err
+		}
+		return &ast.BeginExpr{Span: expr.Span, Body: body}, nil
+
+	case *ast.CaseExpr:
+		key, err := transform(scope, expr.Key)
+		if err != nil {
+			return nil, err
+		}
+		var clauseList []*ast.CaseClause
+		for _, clause := range expr.List {
+			body, err := transformList(scope, clause.Body)
+			if err != nil {
+				return nil, err
+			}
+			clauseList = append(clauseList, &ast.CaseClause{
+				Span:  clause.Span,
+				Else:  clause.Else,
+				Data:  clause.Data,
+				Arrow: clause.Arrow,
+				Body:  body,
+			})
+		}
+		return &ast.CaseExpr{Span: expr.Span, Key: key, List: clauseList}, nil
 
 	case *ast.Quote:
 		return intermediate, nil
@@ -133,3 +237,15 @@ func transform(scope *ast.Scope, input ast.Expr) (ast.Expr, error) {
 	}
 	// transform sub-expressions
 }
+
+func transformList(scope *ast.Scope, list []ast.Expr) ([]ast.Expr, error) {
+	var result []ast.Expr
+	for i := range list {
+		expr, err := transform(scope, list[i])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, expr)
+	}
+	return result, nil
+}
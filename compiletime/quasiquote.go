@@ -0,0 +1,157 @@
+package compiletime
+
+import (
+	"errors"
+
+	"github.com/dyzsr/mylisp/ast"
+)
+
+// quasiquoteSyntax expands (quasiquote template) into the calls to cons, list,
+// append and list->vector which build the template. The parts of the template
+// without any unquote are quoted as they are.
+//
+// The procedures are called by the names '#cons', '#list', '#append' and
+// '#list->vector', which the runtime binds to the built-ins. They can not be
+// read, so the variables of the template can not shadow them.
+func quasiquoteSyntax(scope *ast.Scope, input *ast.ListExpr) (ast.Expr, error) {
+	if len(input.List) != 2 {
+		return nil, errors.New("quasiquote: bad syntax")
+	}
+	return quasi(input.List[1], 1)
+}
+
+func unquoteSyntax(scope *ast.Scope, input *ast.ListExpr) (ast.Expr, error) {
+	return nil, errors.New("unquote: not in quasiquote")
+}
+
+func unquoteSplicingSyntax(scope *ast.Scope, input *ast.ListExpr) (ast.Expr, error) {
+	return nil, errors.New("unquote-splicing: not in quasiquote")
+}
+
+// quasi expands a template nested in depth levels of quasiquote. Only the
+// unquotes at depth 1 are evaluated; the inner ones are kept as data.
+func quasi(tmpl ast.Expr, depth int) (ast.Expr, error) {
+	switch expr := tmpl.(type) {
+	case *ast.ListExpr:
+		if name, ok := quasiForm(expr); ok {
+			switch {
+			case name == "unquote" && depth == 1:
+				return expr.List[1], nil
+			case name == "unquote-splicing" && depth == 1:
+				return nil, ast.NewError(ast.CompilePhase, expr, errors.New("unquote-splicing: not in a list"))
+			case name == "quasiquote":
+				return quasiList(expr, expr.List, nil, depth+1)
+			}
+			return quasiList(expr, expr.List, nil, depth-1)
+		}
+		return quasiList(expr, expr.List, nil, depth)
+	case *ast.DottedList:
+		return quasiList(expr, expr.List, expr.Tail, depth)
+	case *ast.VectorLit:
+		list, err := quasiList(expr, expr.List, nil, depth)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := list.(*ast.Quote); ok {
+			return list, nil
+		}
+		return quasiCall(expr, "list->vector", list), nil
+	}
+	return quasiQuote(tmpl), nil
+}
+
+// quasiForm reports whether expr is one of (quasiquote x), (unquote x) and
+// (unquote-splicing x), and returns the name of its head.
+func quasiForm(expr *ast.ListExpr) (string, bool) {
+	if len(expr.List) != 2 {
+		return "", false
+	}
+	ident, ok := expr.List[0].(*ast.Ident)
+	if !ok {
+		return "", false
+	}
+	switch *ident.Name {
+	case "quasiquote", "unquote", "unquote-splicing":
+		return *ident.Name, true
+	}
+	return "", false
+}
+
+// quasiList expands the items and the tail, which may be nil, of a list
+// template at depth. The result is the quoted template if none of the items
+// contains an unquote at depth 1.
+func quasiList(tmpl ast.Expr, items []ast.Expr, tail ast.Expr, depth int) (ast.Expr, error) {
+	constant := true
+	exprs := make([]ast.Expr, len(items))
+	splices := make([]bool, len(items))
+	for i, item := range items {
+		if list, ok := item.(*ast.ListExpr); ok && depth == 1 {
+			if name, ok := quasiForm(list); ok && name == "unquote-splicing" {
+				exprs[i], splices[i], constant = list.List[1], true, false
+				continue
+			}
+		}
+		expr, err := quasi(item, depth)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := expr.(*ast.Quote); !ok {
+			constant = false
+		}
+		exprs[i] = expr
+	}
+	// rest is nil for the empty list
+	var rest ast.Expr
+	if tail != nil {
+		expr, err := quasi(tail, depth)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := expr.(*ast.Quote); !ok {
+			constant = false
+		}
+		rest = expr
+	}
+	if constant {
+		return quasiQuote(tmpl), nil
+	}
+
+	// build the list from its end: a spliced item is appended to the rest,
+	// and a run of the other items is consed onto it, or makes a new list
+	for i := len(exprs) - 1; i >= 0; {
+		if splices[i] {
+			if rest == nil {
+				rest = exprs[i]
+			} else {
+				rest = quasiCall(tmpl, "append", exprs[i], rest)
+			}
+			i--
+			continue
+		}
+		j := i
+		for j >= 0 && !splices[j] {
+			j--
+		}
+		if rest == nil {
+			rest = quasiCall(tmpl, "list", exprs[j+1:i+1]...)
+		} else {
+			for k := i; k > j; k-- {
+				rest = quasiCall(tmpl, "cons", exprs[k], rest)
+			}
+		}
+		i = j
+	}
+	return rest, nil
+}
+
+func quasiQuote(tmpl ast.Expr) ast.Expr {
+	return &ast.Quote{Span: ast.Span{From: tmpl.Pos(), To: tmpl.End()}, Expr: tmpl}
+}
+
+// quasiCall makes a call to the built-in procedure name, located at tmpl so
+// that the errors it raises point to the template.
+func quasiCall(tmpl ast.Expr, name string, args ...ast.Expr) ast.Expr {
+	ident := ast.NewIdent("#" + name)
+	ident.Span = ast.Span{From: tmpl.Pos(), To: tmpl.End()}
+	return &ast.ListExpr{Span: ident.Span, List: append([]ast.Expr{ident}, args...)}
+}
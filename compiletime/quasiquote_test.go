@@ -0,0 +1,95 @@
+package compiletime
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dyzsr/mylisp/ast"
+)
+
+func Test_transformQuasiquote(t *testing.T) {
+	a, x, xs := ast.NewIdent("a"), ast.NewIdent("x"), ast.NewIdent("xs")
+	list := func(items ...ast.Expr) *ast.ListExpr {
+		return &ast.ListExpr{List: items}
+	}
+	call := func(name string, args ...ast.Expr) *ast.ListExpr {
+		return list(append([]ast.Expr{ast.NewIdent(name)}, args...)...)
+	}
+	builtin := func(name string, args ...ast.Expr) *ast.ListExpr {
+		return call("#"+name, args...)
+	}
+	quote := func(expr ast.Expr) *ast.Quote {
+		return &ast.Quote{Expr: expr}
+	}
+	one := &ast.IntLit{Value: 1}
+
+	testData := []struct {
+		input  ast.Expr
+		result ast.Expr
+	}{
+		{
+			input:  call("quasiquote", list(a, one)),
+			result: quote(list(a, one)),
+		},
+		{
+			input:  call("quasiquote", list(a, call("unquote", x))),
+			result: builtin("list", quote(a), x),
+		},
+		{
+			input:  call("quasiquote", list(a, call("unquote-splicing", xs), one)),
+			result: builtin("cons", quote(a), builtin("append", xs, builtin("list", quote(one)))),
+		},
+		{
+			input:  call("quasiquote", list(call("unquote-splicing", xs))),
+			result: xs,
+		},
+		{
+			input:  call("quasiquote", &ast.DottedList{List: []ast.Expr{a}, Tail: call("unquote", x)}),
+			result: builtin("cons", quote(a), x),
+		},
+		{
+			input:  call("quasiquote", &ast.VectorLit{List: []ast.Expr{call("unquote", x)}}),
+			result: builtin("list->vector", builtin("list", x)),
+		},
+		{
+			input:  call("quasiquote", call("quasiquote", call("unquote", x))),
+			result: quote(call("quasiquote", call("unquote", x))),
+		},
+		{
+			input: call("quasiquote", call("quasiquote", call("unquote", call("unquote", x)))),
+			result: builtin("list", quote(ast.NewIdent("quasiquote")),
+				builtin("list", quote(ast.NewIdent("unquote")), x)),
+		},
+		{
+			input:  call("quasiquote", call("unquote", call("if", x, one, a))),
+			result: &ast.IfExpr{Condition: x, Then: one, Else: a},
+		},
+	}
+
+	scope := ast.NewRootScope()
+	for k, v := range builtinTransformerMap() {
+		scope.Insert(ast.SymbolMap(k), v)
+	}
+	for _, test := range testData {
+		result, err := transform(scope, test.input)
+		if err != nil {
+			t.Error(err)
+			break
+		}
+		if !reflect.DeepEqual(result, test.result) {
+			t.Errorf("\ninput: '%s'\nexpect: %s\noutput: %s", test.input, test.result, result)
+		}
+	}
+
+	badData := []ast.Expr{
+		call("quasiquote"),
+		call("quasiquote", call("unquote-splicing", xs)),
+		call("unquote", x),
+		call("list", call("unquote-splicing", xs)),
+	}
+	for _, input := range badData {
+		if _, err := transform(scope, input); err == nil {
+			t.Errorf("expect an error for '%s'", input)
+		}
+	}
+}
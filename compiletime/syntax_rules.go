@@ -0,0 +1,822 @@
+package compiletime
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+
+	"github.com/dyzsr/mylisp/ast"
+)
+
+// SyntaxRules is a transformer defined by syntax-rules. It rewrites a form
+// with the template of the first rule whose pattern matches the form.
+type SyntaxRules struct {
+	ellipsis *string // nil if the ellipsis is one of the literals
+	literals map[*string]bool
+	rules    []syntaxRule
+}
+
+// syntaxRule is a pattern, without its keyword position, and a template.
+type syntaxRule struct {
+	items    []ast.Expr
+	tail     ast.Expr // nil unless the pattern is an improper list
+	template ast.Expr
+}
+
+// binding is what a pattern variable matches: an expression, or the sequence
+// of bindings of a pattern followed by an ellipsis.
+type binding struct {
+	expr ast.Expr
+	seq  []*binding
+}
+
+type matches map[*string]*binding
+
+var (
+	underscore = ast.SymbolMap("_")
+
+	// renames numbers the fresh names of the renamed bindings
+	renames uint64
+)
+
+// newSyntaxRules parses (syntax-rules [ellipsis] (literal ...) (pattern
+// template) ...).
+func newSyntaxRules(spec *ast.ListExpr) (*SyntaxRules, error) {
+	badSyntaxErr := errors.New("syntax-rules: bad syntax")
+	list := spec.List[1:]
+	t := &SyntaxRules{ellipsis: ast.SymbolMap("..."), literals: map[*string]bool{}}
+	if len(list) > 0 {
+		if ident, ok := list[0].(*ast.Ident); ok {
+			t.ellipsis, list = ident.Name, list[1:]
+		}
+	}
+	if len(list) == 0 {
+		return nil, badSyntaxErr
+	}
+	literals, ok := list[0].(*ast.ListExpr)
+	if !ok {
+		return nil, badSyntaxErr
+	}
+	for _, item := range literals.List {
+		ident, ok := item.(*ast.Ident)
+		if !ok {
+			return nil, badSyntaxErr
+		}
+		t.literals[ident.Name] = true
+		if ident.Name == t.ellipsis {
+			t.ellipsis = nil
+		}
+	}
+	for _, item := range list[1:] {
+		rule, ok := item.(*ast.ListExpr)
+		if !ok || len(rule.List) != 2 {
+			return nil, badSyntaxErr
+		}
+		items, tail, ok := listParts(rule.List[0])
+		if !ok || len(items) == 0 {
+			return nil, badSyntaxErr
+		}
+		if err := t.checkPattern(makeList(items[1:], tail), map[*string]bool{}); err != nil {
+			return nil, err
+		}
+		t.rules = append(t.rules, syntaxRule{items: items[1:], tail: tail, template: rule.List[1]})
+	}
+	return t, nil
+}
+
+func (t *SyntaxRules) Transform(scope *ast.Scope, input *ast.ListExpr) (ast.Expr, error) {
+	name := *input.List[0].(*ast.Ident).Name
+	for _, rule := range t.rules {
+		b := matches{}
+		if !t.matchList(rule.items, rule.tail, input.List[1:], nil, b) {
+			continue
+		}
+		e := &expansion{
+			ellipsis:   t.ellipsis,
+			span:       input.Span,
+			introduced: map[*ast.Ident]bool{},
+		}
+		result, err := e.expand(rule.template, b)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		e.rename(result)
+		return result, nil
+	}
+	return nil, fmt.Errorf("%s: bad syntax", name)
+}
+
+func (t *SyntaxRules) isEllipsis(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && t.ellipsis != nil && ident.Name == t.ellipsis
+}
+
+// checkPattern checks that each pattern variable occurs once, and that an
+// ellipsis follows an item and occurs at most once in a list.
+func (t *SyntaxRules) checkPattern(pattern ast.Expr, vars map[*string]bool) error {
+	var items []ast.Expr
+	var tail ast.Expr
+	switch p := pattern.(type) {
+	case *ast.Ident:
+		switch {
+		case t.isEllipsis(p):
+			return errors.New("syntax-rules: misplaced ellipsis")
+		case p.Name == underscore || t.literals[p.Name]:
+		case vars[p.Name]:
+			return fmt.Errorf("syntax-rules: duplicate pattern variable %s", *p.Name)
+		default:
+			vars[p.Name] = true
+		}
+		return nil
+	case *ast.ListExpr:
+		items = p.List
+	case *ast.DottedList:
+		items, tail = p.List, p.Tail
+	case *ast.VectorLit:
+		items = p.List
+	default:
+		return nil
+	}
+	seen := false
+	for i, item := range items {
+		if t.isEllipsis(item) {
+			if i == 0 || seen {
+				return errors.New("syntax-rules: misplaced ellipsis")
+			}
+			seen = true
+			continue
+		}
+		if err := t.checkPattern(item, vars); err != nil {
+			return err
+		}
+	}
+	if tail != nil {
+		return t.checkPattern(tail, vars)
+	}
+	return nil
+}
+
+// patternVars returns the pattern variables of pattern.
+func (t *SyntaxRules) patternVars(pattern ast.Expr) []*string {
+	switch p := pattern.(type) {
+	case *ast.Ident:
+		if t.isEllipsis(p) || p.Name == underscore || t.literals[p.Name] {
+			return nil
+		}
+		return []*string{p.Name}
+	case *ast.ListExpr:
+		return t.patternListVars(p.List)
+	case *ast.DottedList:
+		return append(t.patternListVars(p.List), t.patternVars(p.Tail)...)
+	case *ast.VectorLit:
+		return t.patternListVars(p.List)
+	}
+	return nil
+}
+
+func (t *SyntaxRules) patternListVars(list []ast.Expr) []*string {
+	var vars []*string
+	for _, item := range list {
+		vars = append(vars, t.patternVars(item)...)
+	}
+	return vars
+}
+
+// match reports whether input matches pattern, and records the bindings of
+// the pattern variables in b.
+func (t *SyntaxRules) match(pattern, input ast.Expr, b matches) bool {
+	switch p := pattern.(type) {
+	case *ast.Ident:
+		switch {
+		case p.Name == underscore:
+		case t.literals[p.Name]:
+			ident, ok := input.(*ast.Ident)
+			return ok && ident.Name == p.Name
+		default:
+			b[p.Name] = &binding{expr: input}
+		}
+		return true
+	case *ast.ListExpr:
+		items, tail, ok := listParts(input)
+		return ok && t.matchList(p.List, nil, items, tail, b)
+	case *ast.DottedList:
+		items, tail, ok := listParts(input)
+		return ok && t.matchList(p.List, p.Tail, items, tail, b)
+	case *ast.VectorLit:
+		vector, ok := input.(*ast.VectorLit)
+		return ok && t.matchList(p.List, nil, vector.List, nil, b)
+	}
+	return sameDatum(pattern, input)
+}
+
+// matchList matches the items and the tail of a list, where the tails are nil
+// for proper lists. An item followed by an ellipsis matches as many items as
+// the rest of the pattern leaves.
+func (t *SyntaxRules) matchList(patterns []ast.Expr, patternTail ast.Expr, items []ast.Expr, tail ast.Expr, b matches) bool {
+	k := -1
+	for i := 1; i < len(patterns); i++ {
+		if t.isEllipsis(patterns[i]) {
+			k = i - 1
+			break
+		}
+	}
+	if k < 0 {
+		if len(items) < len(patterns) || patternTail == nil && (len(items) > len(patterns) || tail != nil) {
+			return false
+		}
+		for i, pattern := range patterns {
+			if !t.match(pattern, items[i], b) {
+				return false
+			}
+		}
+		return patternTail == nil || t.match(patternTail, makeList(items[len(patterns):], tail), b)
+	}
+
+	before, repeated, after := patterns[:k], patterns[k], patterns[k+2:]
+	n := len(items) - len(before) - len(after)
+	if n < 0 || patternTail == nil && tail != nil {
+		return false
+	}
+	for i, pattern := range before {
+		if !t.match(pattern, items[i], b) {
+			return false
+		}
+	}
+	vars := t.patternVars(repeated)
+	for _, v := range vars {
+		b[v] = &binding{seq: []*binding{}}
+	}
+	for _, item := range items[k : k+n] {
+		inner := matches{}
+		if !t.match(repeated, item, inner) {
+			return false
+		}
+		for _, v := range vars {
+			b[v].seq = append(b[v].seq, inner[v])
+		}
+	}
+	for i, pattern := range after {
+		if !t.match(pattern, items[k+n+i], b) {
+			return false
+		}
+	}
+	return patternTail == nil || t.match(patternTail, makeList(nil, tail), b)
+}
+
+// sameDatum reports whether two literals have the same value.
+func sameDatum(a, b ast.Expr) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+	va, vb := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+	if va.Kind() != reflect.Struct {
+		return false
+	}
+	fa, fb := va.FieldByName("Value"), vb.FieldByName("Value")
+	return fa.IsValid() && reflect.DeepEqual(fa.Interface(), fb.Interface())
+}
+
+// listParts returns the items and the tail of a list, where the tail is nil
+// for a proper list.
+func listParts(expr ast.Expr) ([]ast.Expr, ast.Expr, bool) {
+	switch list := expr.(type) {
+	case *ast.ListExpr:
+		return list.List, nil, true
+	case *ast.DottedList:
+		return list.List, list.Tail, true
+	}
+	return nil, nil, false
+}
+
+// makeList makes the list of items ending in tail, which is nil for a proper
+// list.
+func makeList(items []ast.Expr, tail ast.Expr) ast.Expr {
+	switch {
+	case tail == nil:
+		return &ast.ListExpr{List: items}
+	case len(items) == 0:
+		return tail
+	}
+	return &ast.DottedList{List: items, Tail: tail}
+}
+
+// expansion instantiates a template at a macro use.
+type expansion struct {
+	ellipsis *string // nil inside (... template)
+	span     ast.Span
+	// introduced are the identifiers copied from the template, rather than
+	// from the macro use
+	introduced map[*ast.Ident]bool
+}
+
+func (e *expansion) isEllipsis(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && e.ellipsis != nil && ident.Name == e.ellipsis
+}
+
+func (e *expansion) expand(tmpl ast.Expr, b matches) (ast.Expr, error) {
+	switch t := tmpl.(type) {
+	case *ast.Ident:
+		if v, ok := b[t.Name]; ok {
+			if v.expr == nil {
+				return nil, fmt.Errorf("missing ellipsis after %s", *t.Name)
+			}
+			return v.expr, nil
+		}
+		ident := &ast.Ident{Span: e.span, Name: t.Name}
+		e.introduced[ident] = true
+		return ident, nil
+	case *ast.ListExpr:
+		// (... template) escapes the ellipses in template
+		if len(t.List) == 2 && e.isEllipsis(t.List[0]) {
+			ellipsis := e.ellipsis
+			e.ellipsis = nil
+			defer func() { e.ellipsis = ellipsis }()
+			return e.expand(t.List[1], b)
+		}
+		items, err := e.expandItems(t.List, b)
+		if err != nil {
+			return nil, err
+		}
+		return &ast.ListExpr{Span: e.span, List: items}, nil
+	case *ast.DottedList:
+		items, err := e.expandItems(t.List, b)
+		if err != nil {
+			return nil, err
+		}
+		tail, err := e.expand(t.Tail, b)
+		if err != nil {
+			return nil, err
+		}
+		if more, rest, ok := listParts(tail); ok {
+			items, tail = append(items, more...), rest
+		}
+		list := makeList(items, tail)
+		ast.SetSpan(list, e.span.From, e.span.To)
+		return list, nil
+	case *ast.VectorLit:
+		items, err := e.expandItems(t.List, b)
+		if err != nil {
+			return nil, err
+		}
+		return &ast.VectorLit{Span: e.span, List: items}, nil
+	}
+	return tmpl, nil
+}
+
+// expandItems expands the items of a list template, where an item followed by
+// n ellipses makes a copy for each of the sequences its pattern variables
+// matched, flattened n times.
+func (e *expansion) expandItems(list []ast.Expr, b matches) ([]ast.Expr, error) {
+	var result []ast.Expr
+	for i := 0; i < len(list); i++ {
+		if e.isEllipsis(list[i]) {
+			return nil, errors.New("misplaced ellipsis")
+		}
+		depth := 0
+		for i+depth+1 < len(list) && e.isEllipsis(list[i+depth+1]) {
+			depth++
+		}
+		if depth == 0 {
+			item, err := e.expand(list[i], b)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, item)
+			continue
+		}
+		items, err := e.expandEllipsis(list[i], depth, b)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, items...)
+		i += depth
+	}
+	return result, nil
+}
+
+func (e *expansion) expandEllipsis(tmpl ast.Expr, depth int, b matches) ([]ast.Expr, error) {
+	var vars []*string
+	n := -1
+	for _, v := range templateVars(tmpl, nil) {
+		if bv, ok := b[v]; !ok || bv.expr != nil {
+			continue
+		}
+		if n >= 0 && len(b[v].seq) != n {
+			return nil, errors.New("mismatched ellipsis lengths")
+		}
+		n = len(b[v].seq)
+		vars = append(vars, v)
+	}
+	if vars == nil {
+		return nil, errors.New("no pattern variable before ellipsis")
+	}
+	var result []ast.Expr
+	for i := 0; i < n; i++ {
+		inner := make(matches, len(b))
+		for k, v := range b {
+			inner[k] = v
+		}
+		for _, v := range vars {
+			inner[v] = b[v].seq[i]
+		}
+		if depth > 1 {
+			items, err := e.expandEllipsis(tmpl, depth-1, inner)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, items...)
+			continue
+		}
+		item, err := e.expand(tmpl, inner)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// templateVars appends the names of the identifiers in tmpl to vars.
+func templateVars(tmpl ast.Expr, vars []*string) []*string {
+	switch t := tmpl.(type) {
+	case *ast.Ident:
+		for _, v := range vars {
+			if v == t.Name {
+				return vars
+			}
+		}
+		return append(vars, t.Name)
+	case *ast.ListExpr:
+		for _, item := range t.List {
+			vars = templateVars(item, vars)
+		}
+	case *ast.DottedList:
+		for _, item := range t.List {
+			vars = templateVars(item, vars)
+		}
+		vars = templateVars(t.Tail, vars)
+	case *ast.VectorLit:
+		for _, item := range t.List {
+			vars = templateVars(item, vars)
+		}
+	}
+	return vars
+}
+
+// renaming maps the names of the variables bound by a template to their fresh
+// names.
+type renaming map[*string]*string
+
+// rename gives fresh names to the variables the template binds, and to the
+// introduced references within their scopes, so that they neither capture nor
+// shadow the identifiers from the macro use. A fresh name contains a '#', so
+// that it can not be read. The top-level definitions keep their names.
+func (e *expansion) rename(expr ast.Expr) {
+	e.renameCode(expr, renaming{})
+}
+
+// renameCode renames the introduced references in expr, which are bound in
+// env, and the bindings which expr introduces itself.
+func (e *expansion) renameCode(expr ast.Expr, env renaming) {
+	if ident, ok := expr.(*ast.Ident); ok {
+		e.renameRef(ident, env)
+		return
+	}
+	list, ok := expr.(*ast.ListExpr)
+	if !ok || len(list.List) == 0 {
+		return
+	}
+	args := list.List[1:]
+	// the head is a keyword unless the template binds it as a variable
+	if head, ok := list.List[0].(*ast.Ident); ok && len(args) > 0 && !(e.introduced[head] && env[head.Name] != nil) {
+		switch *head.Name {
+		case "quote":
+			return
+		case "quasiquote":
+			e.renameQuasi(args[0], env, 1)
+			return
+		case "lambda":
+			e.renameBody(args[1:], e.bindFormals(args[0], env))
+			return
+		case "case-lambda":
+			for _, arg := range args {
+				if clause, ok := arg.(*ast.ListExpr); ok && len(clause.List) > 0 {
+					e.renameBody(clause.List[1:], e.bindFormals(clause.List[0], env))
+				}
+			}
+			return
+		case "define":
+			e.renameDefine(args, env)
+			return
+		case "let", "let*", "letrec", "letrec*":
+			if e.renameLet(*head.Name, args, env) {
+				return
+			}
+		case "case":
+			e.renameCode(args[0], env)
+			for _, arg := range args[1:] {
+				// the data of a clause are not renamed
+				if clause, ok := arg.(*ast.ListExpr); ok && len(clause.List) > 0 {
+					for _, item := range clause.List[1:] {
+						e.renameCode(item, env)
+					}
+				}
+			}
+			return
+		}
+	}
+	for _, item := range list.List {
+		e.renameCode(item, env)
+	}
+}
+
+func (e *expansion) renameRef(ident *ast.Ident, env renaming) {
+	if fresh := env[ident.Name]; fresh != nil && e.introduced[ident] {
+		ident.Name = fresh
+	}
+}
+
+// bind returns env extended with fresh names for the introduced identifiers
+// among binders, which are renamed.
+func (e *expansion) bind(env renaming, binders []ast.Expr) renaming {
+	inner := make(renaming, len(env))
+	for k, v := range env {
+		inner[k] = v
+	}
+	for _, binder := range binders {
+		ident, ok := binder.(*ast.Ident)
+		if !ok || !e.introduced[ident] {
+			continue
+		}
+		n := atomic.AddUint64(&renames, 1)
+		fresh := ast.SymbolMap(fmt.Sprintf("%s#%d", *ident.Name, n))
+		inner[ident.Name], ident.Name = fresh, fresh
+	}
+	return inner
+}
+
+// bindFormals binds the parameters of a lambda, whose default values see the
+// parameters as well.
+func (e *expansion) bindFormals(formals ast.Expr, env renaming) renaming {
+	items, tail, ok := listParts(formals)
+	if !ok {
+		return e.bind(env, []ast.Expr{formals})
+	}
+	var binders, defaults []ast.Expr
+	for _, item := range items {
+		switch param := item.(type) {
+		case *ast.Ident:
+			if *param.Name != "#!optional" && *param.Name != "#!rest" {
+				binders = append(binders, param)
+			}
+		case *ast.ListExpr:
+			if len(param.List) > 0 {
+				binders = append(binders, param.List[0])
+				defaults = append(defaults, param.List[1:]...)
+			}
+		}
+	}
+	if tail != nil {
+		binders = append(binders, tail)
+	}
+	inner := e.bind(env, binders)
+	for _, expr := range defaults {
+		e.renameCode(expr, inner)
+	}
+	return inner
+}
+
+// renameBody binds the internal definitions of a body, which are visible in
+// the whole body.
+func (e *expansion) renameBody(body []ast.Expr, env renaming) {
+	inner := e.bind(env, definitions(body, nil))
+	for _, expr := range body {
+		e.renameCode(expr, inner)
+	}
+}
+
+// definitions appends the names defined by the definitions in body, including
+// those in a begin, to names.
+func definitions(body []ast.Expr, names []ast.Expr) []ast.Expr {
+	for _, expr := range body {
+		list, ok := expr.(*ast.ListExpr)
+		if !ok || len(list.List) < 2 {
+			continue
+		}
+		head, ok := list.List[0].(*ast.Ident)
+		if !ok {
+			continue
+		}
+		switch *head.Name {
+		case "begin":
+			names = definitions(list.List[1:], names)
+		case "define":
+			target := list.List[1]
+			for {
+				items, _, ok := listParts(target)
+				if !ok || len(items) == 0 {
+					break
+				}
+				target = items[0]
+			}
+			names = append(names, target)
+		}
+	}
+	return names
+}
+
+// renameDefine renames a definition, whose name is bound by the enclosing
+// body, if any. The shorthand (define (f . formals) body ...) binds formals in
+// its body.
+func (e *expansion) renameDefine(args []ast.Expr, env renaming) {
+	target := args[0]
+	var formals []ast.Expr
+	for {
+		items, tail, ok := listParts(target)
+		if !ok || len(items) == 0 {
+			break
+		}
+		formals = append(formals, makeList(items[1:], tail))
+		target = items[0]
+	}
+	if ident, ok := target.(*ast.Ident); ok {
+		e.renameRef(ident, env)
+	}
+	if formals == nil {
+		for _, expr := range args[1:] {
+			e.renameCode(expr, env)
+		}
+		return
+	}
+	// the formals of a curried definition are listed from the innermost
+	// lambda
+	inner := env
+	for i := len(formals) - 1; i >= 0; i-- {
+		inner = e.bindFormals(formals[i], inner)
+	}
+	e.renameBody(args[1:], inner)
+}
+
+// renameLet renames a let form, and reports whether it is well formed.
+func (e *expansion) renameLet(name string, args []ast.Expr, env renaming) bool {
+	var self ast.Expr
+	if ident, ok := args[0].(*ast.Ident); ok && name == "let" {
+		self, args = ident, args[1:]
+	}
+	if len(args) == 0 {
+		return false
+	}
+	list, ok := args[0].(*ast.ListExpr)
+	if !ok {
+		return false
+	}
+	var vars []ast.Expr
+	var inits [][]ast.Expr
+	for _, item := range list.List {
+		binding, ok := item.(*ast.ListExpr)
+		if !ok || len(binding.List) == 0 {
+			return false
+		}
+		vars, inits = append(vars, binding.List[0]), append(inits, binding.List[1:])
+	}
+
+	inner := env
+	switch name {
+	case "let":
+		for _, init := range inits {
+			for _, expr := range init {
+				e.renameCode(expr, env)
+			}
+		}
+		// a named let binds its name around the variables
+		if self != nil {
+			inner = e.bind(inner, []ast.Expr{self})
+		}
+		inner = e.bind(inner, vars)
+	case "let*":
+		for i, init := range inits {
+			for _, expr := range init {
+				e.renameCode(expr, inner)
+			}
+			inner = e.bind(inner, vars[i:i+1])
+		}
+	default:
+		inner = e.bind(inner, vars)
+		for _, init := range inits {
+			for _, expr := range init {
+				e.renameCode(expr, inner)
+			}
+		}
+	}
+	e.renameBody(args[1:], inner)
+	return true
+}
+
+// renameQuasi renames the unquoted parts of a quasiquote template nested in
+// depth levels of quasiquote.
+func (e *expansion) renameQuasi(tmpl ast.Expr, env renaming, depth int) {
+	var items []ast.Expr
+	switch t := tmpl.(type) {
+	case *ast.ListExpr:
+		if name, ok := quasiForm(t); ok {
+			switch {
+			case name == "quasiquote":
+				e.renameQuasi(t.List[1], env, depth+1)
+			case depth == 1:
+				e.renameCode(t.List[1], env)
+			default:
+				e.renameQuasi(t.List[1], env, depth-1)
+			}
+			return
+		}
+		items = t.List
+	case *ast.DottedList:
+		items = append(append([]ast.Expr{}, t.List...), t.Tail)
+	case *ast.VectorLit:
+		items = t.List
+	}
+	for _, item := range items {
+		e.renameQuasi(item, env, depth)
+	}
+}
+
+// syntaxRulesOf parses the transformer of a syntax definition, which must be
+// a syntax-rules form.
+func syntaxRulesOf(expr ast.Expr) (*SyntaxRules, bool, error) {
+	spec, ok := expr.(*ast.ListExpr)
+	if !ok || len(spec.List) == 0 {
+		return nil, false, nil
+	}
+	if head, ok := spec.List[0].(*ast.Ident); !ok || *head.Name != "syntax-rules" {
+		return nil, false, nil
+	}
+	t, err := newSyntaxRules(spec)
+	return t, true, err
+}
+
+func defineSyntaxSyntax(scope *ast.Scope, input *ast.ListExpr) (ast.Expr, error) {
+	badSyntaxErr := errors.New("define-syntax: bad syntax")
+	if len(input.List) != 3 {
+		return nil, badSyntaxErr
+	}
+	ident, ok := input.List[1].(*ast.Ident)
+	if !ok {
+		return nil, badSyntaxErr
+	}
+	t, ok, err := syntaxRulesOf(input.List[2])
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, badSyntaxErr
+	}
+	scope.Insert(ident.Name, t)
+	return &ast.BeginExpr{Span: input.Span}, nil
+}
+
+// letSyntaxSyntax binds the keywords of a let-syntax or a letrec-syntax in a
+// new scope, where the body is transformed as the body of a lambda without
+// parameters which is called at once. The templates are expanded in the
+// scope of the macro uses, so both forms are the same.
+func letSyntaxSyntax(scope *ast.Scope, input *ast.ListExpr) (ast.Expr, error) {
+	name := *input.List[0].(*ast.Ident).Name
+	badSyntaxErr := fmt.Errorf("%s: bad syntax", name)
+	if len(input.List) < 3 {
+		return nil, badSyntaxErr
+	}
+	list, ok := input.List[1].(*ast.ListExpr)
+	if !ok {
+		return nil, badSyntaxErr
+	}
+	inner := ast.NewScope(scope)
+	for _, item := range list.List {
+		binding, ok := item.(*ast.ListExpr)
+		if !ok || len(binding.List) != 2 {
+			return nil, badSyntaxErr
+		}
+		ident, ok := binding.List[0].(*ast.Ident)
+		if !ok {
+			return nil, badSyntaxErr
+		}
+		t, ok, err := syntaxRulesOf(binding.List[1])
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, badSyntaxErr
+		}
+		inner.Insert(ident.Name, t)
+	}
+	body, err := transformList(inner, input.List[2:])
+	if err != nil {
+		return nil, err
+	}
+	lambda := &ast.LambdaExpr{Span: input.Span, Body: body}
+	return &ast.ListExpr{Span: input.Span, List: []ast.Expr{lambda}}, nil
+}
+
+func syntaxRulesSyntax(scope *ast.Scope, input *ast.ListExpr) (ast.Expr, error) {
+	return nil, errors.New("syntax-rules: not in a syntax definition")
+}
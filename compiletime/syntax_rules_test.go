@@ -0,0 +1,169 @@
+package compiletime
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dyzsr/mylisp/ast"
+)
+
+func Test_syntaxRulesRename(t *testing.T) {
+	a, x, tmp := ast.NewIdent("a"), ast.NewIdent("x"), ast.NewIdent("tmp")
+	list := func(items ...ast.Expr) *ast.ListExpr {
+		return &ast.ListExpr{List: items}
+	}
+	call := func(name string, args ...ast.Expr) *ast.ListExpr {
+		return list(append([]ast.Expr{ast.NewIdent(name)}, args...)...)
+	}
+
+	// (define-syntax m (syntax-rules () ((_ a) (let ((tmp a)) (list tmp 'tmp x)))))
+	scope := ast.NewRootScope()
+	for k, v := range builtinTransformerMap() {
+		scope.Insert(ast.SymbolMap(k), v)
+	}
+	template := call("let", list(list(tmp, a)), call("list", tmp, call("quote", tmp), x))
+	rules := call("syntax-rules", list(), list(list(ast.NewIdent("_"), a), template))
+	if _, err := transform(scope, call("define-syntax", ast.NewIdent("m"), rules)); err != nil {
+		t.Fatal(err)
+	}
+
+	value, _ := scope.Lookup(ast.SymbolMap("m"))
+	result, err := value.(Transformer).Transform(scope, call("m", tmp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// (let ((tmp#n tmp)) (list tmp#n 'tmp x))
+	let := result.(*ast.ListExpr)
+	binding := let.List[1].(*ast.ListExpr).List[0].(*ast.ListExpr)
+	body := let.List[2].(*ast.ListExpr)
+	renamed := binding.List[0].(*ast.Ident).Name
+	if !strings.HasPrefix(*renamed, "tmp#") {
+		t.Errorf("expect tmp to be renamed, output: %s", result)
+	}
+	if binding.List[1] != tmp {
+		t.Errorf("expect the argument to be kept, output: %s", binding.List[1])
+	}
+	if body.List[1].(*ast.Ident).Name != renamed {
+		t.Errorf("expect the reference to be renamed, output: %s", body.List[1])
+	}
+	if quoted := body.List[2].(*ast.ListExpr).List[1].(*ast.Ident); quoted.Name != tmp.Name {
+		t.Errorf("expect quoted data to be kept, output: %s", quoted)
+	}
+	if free := body.List[3].(*ast.Ident); free.Name != x.Name {
+		t.Errorf("expect a free identifier to be kept, output: %s", free)
+	}
+}
+
+func Test_syntaxRulesRenameScope(t *testing.T) {
+	x, y := ast.NewIdent("x"), ast.NewIdent("y")
+	list := func(items ...ast.Expr) *ast.ListExpr {
+		return &ast.ListExpr{List: items}
+	}
+	call := func(name string, args ...ast.Expr) *ast.ListExpr {
+		return list(append([]ast.Expr{ast.NewIdent(name)}, args...)...)
+	}
+	one := &ast.IntLit{Value: 1}
+
+	// (begin (define y x) (list x (let ((x 1)) x) (lambda (y) y)) y)
+	template := call("begin",
+		call("define", y, x),
+		call("list", x, call("let", list(list(x, one)), x), call("lambda", list(y), y)),
+		y)
+	scope := ast.NewRootScope()
+	for k, v := range builtinTransformerMap() {
+		scope.Insert(ast.SymbolMap(k), v)
+	}
+	rules := call("syntax-rules", list(), list(list(ast.NewIdent("_")), template))
+	if _, err := transform(scope, call("define-syntax", ast.NewIdent("m"), rules)); err != nil {
+		t.Fatal(err)
+	}
+	value, _ := scope.Lookup(ast.SymbolMap("m"))
+	result, err := value.(Transformer).Transform(scope, call("m"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name := func(expr ast.Expr) string { return *expr.(*ast.Ident).Name }
+	begin := result.(*ast.ListExpr)
+	define, body := begin.List[1].(*ast.ListExpr), begin.List[2].(*ast.ListExpr)
+	let, lambda := body.List[2].(*ast.ListExpr), body.List[3].(*ast.ListExpr)
+	letVar := let.List[1].(*ast.ListExpr).List[0].(*ast.ListExpr).List[0]
+	param := lambda.List[1].(*ast.ListExpr).List[0]
+
+	testData := []struct {
+		expr   ast.Expr
+		expect string
+	}{
+		{define.List[1], "y"}, // a top-level definition keeps its name
+		{define.List[2], "x"}, // free outside the let
+		{body.List[1], "x"},   // free outside the let
+		{let.List[2], name(letVar)},
+		{lambda.List[2], name(param)},
+		{begin.List[3], "y"}, // refers to the definition
+	}
+	for _, test := range testData {
+		if name(test.expr) != test.expect {
+			t.Errorf("\nexpansion: %s\nexpect: %s\noutput: %s", result, test.expect, name(test.expr))
+		}
+	}
+	if name(letVar) == "x" || name(param) == "y" {
+		t.Errorf("expect the local variables to be renamed, output: %s", result)
+	}
+}
+
+func Test_syntaxRulesBad(t *testing.T) {
+	a, dots := ast.NewIdent("a"), ast.NewIdent("...")
+	list := func(items ...ast.Expr) *ast.ListExpr {
+		return &ast.ListExpr{List: items}
+	}
+	call := func(name string, args ...ast.Expr) *ast.ListExpr {
+		return list(append([]ast.Expr{ast.NewIdent(name)}, args...)...)
+	}
+	define := func(rules ...ast.Expr) ast.Expr {
+		return call("define-syntax", ast.NewIdent("m"), call("syntax-rules", rules...))
+	}
+	rule := func(pattern ...ast.Expr) ast.Expr {
+		return list(list(append([]ast.Expr{ast.NewIdent("_")}, pattern...)...), a)
+	}
+
+	badData := []ast.Expr{
+		call("define-syntax", ast.NewIdent("m")),
+		call("define-syntax", ast.NewIdent("m"), call("lambda", a, a)),
+		define(),
+		define(a),
+		define(list(), list(a)),
+		define(list(), rule(a, a)),
+		define(list(), rule(dots)),
+		define(list(), rule(a, dots, a, dots)),
+		call("let-syntax", list(list(a)), a),
+		call("syntax-rules", list()),
+	}
+	scope := ast.NewRootScope()
+	for k, v := range builtinTransformerMap() {
+		scope.Insert(ast.SymbolMap(k), v)
+	}
+	for _, input := range badData {
+		if _, err := transform(scope, input); err == nil {
+			t.Errorf("expect an error for '%s'", input)
+		}
+	}
+
+	// the template uses a without an ellipsis
+	if _, err := transform(scope, define(list(), list(list(ast.NewIdent("_"), a, dots), a))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := transform(scope, call("m", a)); err == nil {
+		t.Error("expect an error for a missing ellipsis")
+	}
+
+	// the macro expands into itself forever
+	if _, err := transform(scope, define(list(), list(list(ast.NewIdent("_")), call("m")))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := transform(scope, call("m")); err == nil {
+		t.Error("expect an error for an endless expansion")
+	}
+	if expansions != 0 {
+		t.Errorf("expect the expansions to be unwound, output: %d", expansions)
+	}
+}
@@ -11,6 +11,9 @@ type Config struct {
 }
 
 func GetConfig() (*Config, error) {
+	if !flag.Parsed() {
+		flag.Parse()
+	}
 	// get input filenames from console
 	filenames := flag.Args()
 
@@ -20,7 +23,7 @@ func GetConfig() (*Config, error) {
 		fromStdin = true
 	} else {
 		for _, filename := range filenames {
-			if _, err := os.Stat(filename); err == nil || os.IsNotExist(err) {
+			if _, err := os.Stat(filename); err != nil {
 				return nil, err
 			}
 		}
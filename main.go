@@ -1,11 +1,13 @@
 package main
 
 import (
+	"io"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/dyzsr/mylisp/compiletime"
+	"github.com/dyzsr/mylisp/config"
 	"github.com/dyzsr/mylisp/parser"
 	"github.com/dyzsr/mylisp/repl"
 	"github.com/dyzsr/mylisp/runtime"
@@ -22,12 +24,37 @@ func main() {
 		}
 	}()
 
-	lex := token.NewLexer(os.Stdin)
-	par := parser.NewParser(lex)
+	eprt := repl.NewErrorPrinter()
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		eprt.Print(err)
+		os.Exit(1)
+	}
+
 	ct := compiletime.NewCompileTime()
 	rt := runtime.NewRuntime()
+
+	if cfg.FromStdin {
+		runInteractive(os.Stdin, ct, rt, eprt)
+		return
+	}
+
+	for _, filename := range cfg.Filenames {
+		if err := runFile(filename, ct, rt, eprt); err != nil {
+			eprt.Print(err)
+			os.Exit(1)
+		}
+	}
+}
+
+// runInteractive evaluates expressions read from reader, reporting errors
+// and carrying on until EOF.
+func runInteractive(reader io.Reader, ct *compiletime.CompileTime, rt *runtime.Runtime, eprt *repl.ErrorPrinter) {
+	lex := token.NewLexer(reader)
+	eprt.AddSource("", lex)
+	par := parser.NewParser(lex)
 	prt := repl.NewPrinter()
-	eprt := repl.NewErrorPrinter()
 
 	for {
 		expr, ok := par.Next()
@@ -55,3 +82,39 @@ func main() {
 		}
 	}
 }
+
+// runFile evaluates every expression in the named file and stops at the
+// first error. The file is registered with eprt so that errors can quote it.
+func runFile(filename string, ct *compiletime.CompileTime, rt *runtime.Runtime, eprt *repl.ErrorPrinter) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	lex := token.NewFileLexer(filename, file)
+	eprt.AddSource(filename, lex)
+	par := parser.NewParser(lex)
+	prt := repl.NewPrinter()
+
+	for {
+		expr, ok := par.Next()
+		if !ok {
+			if err := par.Err(); err != nil {
+				return err
+			}
+			return nil // EOF
+		}
+
+		expr, err := ct.Eval(expr)
+		if err != nil {
+			return err
+		}
+
+		result, err := rt.Eval(expr)
+		if err != nil {
+			return err
+		}
+		prt.Print(result)
+	}
+}
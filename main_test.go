@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/dyzsr/mylisp/ast"
+	"github.com/dyzsr/mylisp/compiletime"
+	"github.com/dyzsr/mylisp/repl"
+	"github.com/dyzsr/mylisp/runtime"
+)
+
+// TestMain runs the interpreter itself when the tests start it as a
+// subprocess, with the files to run as the arguments.
+func TestMain(m *testing.M) {
+	if os.Getenv("MYLISP_RUN_MAIN") == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func writeFile(t *testing.T, content string) string {
+	filename := filepath.Join(t.TempDir(), "test.scm")
+	if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func Test_runFile(t *testing.T) {
+	testData := []struct {
+		content string
+		fail    bool
+		phase   ast.Phase
+	}{
+		{content: "(define x 1)\n(+ x 2)\n"},
+		{content: "(+ 1 2", fail: true, phase: ast.ParsePhase},
+		{content: "(+ 1 2)\n(car 1)\n(+ 3 4)", fail: true, phase: ast.RuntimePhase},
+		{content: "(lambda x)", fail: true, phase: ast.CompilePhase},
+	}
+	for _, test := range testData {
+		filename := writeFile(t, test.content)
+		err := runFile(filename, compiletime.NewCompileTime(), runtime.NewRuntime(), repl.NewErrorPrinter())
+		if !test.fail {
+			if err != nil {
+				t.Errorf("\ninput: '%s'\nerror: %s", test.content, err)
+			}
+			continue
+		}
+		var e *ast.Error
+		if !errors.As(err, &e) || e.Phase != test.phase {
+			t.Errorf("\ninput: '%s'\nexpect: %s error\noutput: %v", test.content, test.phase, err)
+		}
+	}
+}
+
+func Test_runFileExit(t *testing.T) {
+	testData := []struct {
+		content string
+		code    int
+	}{
+		{content: "(+ 1 2)", code: 0},
+		{content: "(+ 1 2", code: 1},
+		{content: "(car 1)", code: 1},
+	}
+	for _, test := range testData {
+		cmd := exec.Command(os.Args[0], writeFile(t, test.content))
+		cmd.Env = append(os.Environ(), "MYLISP_RUN_MAIN=1")
+		err := cmd.Run()
+		code := 0
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			code = exitErr.ExitCode()
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		if code != test.code {
+			t.Errorf("\ninput: '%s'\nexpect exit code: %d\noutput: %d", test.content, test.code, code)
+		}
+	}
+}
@@ -2,11 +2,23 @@ package parser
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/dyzsr/mylisp/ast"
 	"github.com/dyzsr/mylisp/token"
 )
 
+var (
+	// abbreviations maps the quoting prefixes to the forms they abbreviate,
+	// as in 'x for (quote x).
+	abbreviations = map[token.Token]string{
+		token.QUOTE:            "quote",
+		token.QUASIQUOTE:       "quasiquote",
+		token.UNQUOTE:          "unquote",
+		token.UNQUOTE_SPLICING: "unquote-splicing",
+	}
+)
+
 type Parser struct {
 	lexer *token.Lexer
 	err   error
@@ -46,44 +58,154 @@ func (p *Parser) next() (ast.Expr, error) {
 	// fmt.Println("Parser next: start")
 	// defer fmt.Println("Parser next: end")
 	tok, expr := p.lexer.Next()
+	span := p.lexer.Span()
 	// fmt.Printf("tok: '%s'\n", tok)
 
 	switch tok {
 	case token.EOF:
 		return nil, nil
 	case token.RPAREN: // invalid
-		return nil, errors.New("unexpected ')'")
-	case token.QUOTE:
+		return nil, ast.NewError(ast.ParsePhase, &span, errors.New("unexpected ')'"))
+	case token.DOT: // invalid
+		return nil, ast.NewError(ast.ParsePhase, &span, errors.New("unexpected '.'"))
+	case token.ILLEGAL:
+		err := p.lexer.Err()
+		if err == nil {
+			err = errors.New("illegal token")
+		}
+		return nil, ast.NewError(ast.ParsePhase, &span, err)
+	case token.DATUM_COMMENT:
+		if err := p.skipDatum(); err != nil {
+			return nil, err
+		}
+		return p.next()
+	case token.QUOTE, token.QUASIQUOTE, token.UNQUOTE, token.UNQUOTE_SPLICING:
 		node, err := p.next()
 		if err != nil {
 			return nil, err
 		}
+		if node == nil {
+			return nil, ast.NewError(ast.ParsePhase, &span, fmt.Errorf("unexpected EOF after %s", abbreviations[tok]))
+		}
+		quote := ast.NewIdent(abbreviations[tok])
+		quote.Span = span
 		return &ast.ListExpr{
-			List: []ast.Expr{ast.NewIdent("quote"), node},
+			Span: ast.Span{From: span.From, To: node.End()},
+			List: []ast.Expr{quote, node},
 		}, nil
 	}
-	if tok != token.LPAREN { // atom
+	if tok != token.LPAREN && tok != token.VECTOR && tok != token.BYTEVECTOR { // atom
 		// println("atom", expr)
 		return expr, nil
 	}
 
 	// nested
+	list, dot, err := p.items(&span)
+	if err != nil {
+		return nil, err
+	}
+	// fmt.Printf("list: %s\n", list)
+	if dot {
+		if tok != token.LPAREN {
+			p.lexer.Next()
+			dot := p.lexer.Span()
+			return nil, ast.NewError(ast.ParsePhase, &dot, errors.New("unexpected '.'"))
+		}
+		return p.dottedList(span, list)
+	}
+	switch tok {
+	case token.VECTOR:
+		return &ast.VectorLit{Span: span, List: list}, nil
+	case token.BYTEVECTOR:
+		return bytevector(span, list)
+	}
+	return &ast.ListExpr{Span: span, List: list}, nil
+}
+
+// dottedList parses the tail of an improper list after its items, which is
+// a '.', an expression and the closing ')'.
+func (p *Parser) dottedList(span ast.Span, list []ast.Expr) (ast.Expr, error) {
+	p.lexer.Next()
+	dot := p.lexer.Span()
+	if len(list) == 0 {
+		return nil, ast.NewError(ast.ParsePhase, &dot, errors.New("unexpected '.'"))
+	}
+	tail, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if tail == nil {
+		return nil, ast.NewError(ast.ParsePhase, &dot, errors.New("unexpected EOF after '.'"))
+	}
+	rest, again, err := p.items(&span)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, ast.NewError(ast.ParsePhase, rest[0], errors.New("more than one expression after '.'"))
+	}
+	if again {
+		p.lexer.Next()
+		dot := p.lexer.Span()
+		return nil, ast.NewError(ast.ParsePhase, &dot, errors.New("unexpected '.'"))
+	}
+	return &ast.DottedList{Span: span, List: list, Tail: tail}, nil
+}
+
+// items parses the expressions up to the closing ')' of a list or a vector,
+// and extends span, which is that of the opening parenthesis, to the ')'. It
+// stops before a '.', which is left to the caller, and reports whether it did.
+func (p *Parser) items(span *ast.Span) ([]ast.Expr, bool, error) {
+	open := *span
 	var list []ast.Expr
-L:
 	for tok, _ := p.lexer.LookupOne(); tok != token.EOF; tok, _ = p.lexer.LookupOne() {
 		switch tok {
 		case token.RPAREN:
 			// fmt.Printf("tok: '%s'\n", tok)
 			p.lexer.Next()
-			break L
+			span.To = p.lexer.Span().To
+			return list, false, nil
+		case token.DOT:
+			return list, true, nil
+		case token.DATUM_COMMENT:
+			p.lexer.Next()
+			if err := p.skipDatum(); err != nil {
+				return nil, false, err
+			}
 		default:
 			node, err := p.next()
 			if err != nil {
-				return nil, err
+				return nil, false, err
 			}
 			list = append(list, node)
 		}
 	}
-	// fmt.Printf("list: %s\n", list)
-	return &ast.ListExpr{List: list}, nil
+	return nil, false, ast.NewError(ast.ParsePhase, &open, errors.New("unexpected EOF"))
+}
+
+// bytevector makes a bytevector literal of its items, which must be integers
+// from 0 to 255.
+func bytevector(span ast.Span, list []ast.Expr) (ast.Expr, error) {
+	value := make([]byte, len(list))
+	for i, item := range list {
+		lit, ok := item.(*ast.IntLit)
+		if !ok || lit.Value < 0 || lit.Value > 255 {
+			return nil, ast.NewError(ast.ParsePhase, item, errors.New("bad byte in bytevector"))
+		}
+		value[i] = byte(lit.Value)
+	}
+	return &ast.BytevectorLit{Span: span, Value: value}, nil
+}
+
+// skipDatum discards the expression following a '#;'.
+func (p *Parser) skipDatum() error {
+	span := p.lexer.Span()
+	expr, err := p.next()
+	if err != nil {
+		return err
+	}
+	if expr == nil {
+		return ast.NewError(ast.ParsePhase, &span, errors.New("unexpected EOF after '#;'"))
+	}
+	return nil
 }
@@ -60,6 +60,72 @@ func Test_next(t *testing.T) {
 				},
 			},
 		},
+		{
+			input: "; comment\n#;(skipped) (f #| block |# #;(g #;x) y #;z)",
+			result: &ast.ListExpr{
+				List: []ast.Expr{
+					ast.NewIdent("f"),
+					ast.NewIdent("y"),
+				},
+			},
+		},
+		{
+			input:  "#u8(0 #;256 255)",
+			result: &ast.BytevectorLit{Value: []byte{0, 255}},
+		},
+		{
+			input: "#(1 #(a) (b))",
+			result: &ast.VectorLit{
+				List: []ast.Expr{
+					&ast.IntLit{Value: 1},
+					&ast.VectorLit{
+						List: []ast.Expr{ast.NewIdent("a")},
+					},
+					&ast.ListExpr{
+						List: []ast.Expr{ast.NewIdent("b")},
+					},
+				},
+			},
+		},
+		{
+			input: "`(a ,b ,@c)",
+			result: &ast.ListExpr{
+				List: []ast.Expr{
+					ast.NewIdent("quasiquote"),
+					&ast.ListExpr{
+						List: []ast.Expr{
+							ast.NewIdent("a"),
+							&ast.ListExpr{
+								List: []ast.Expr{ast.NewIdent("unquote"), ast.NewIdent("b")},
+							},
+							&ast.ListExpr{
+								List: []ast.Expr{ast.NewIdent("unquote-splicing"), ast.NewIdent("c")},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			input: "'(1 (a . b) . #;x (c))",
+			result: &ast.ListExpr{
+				List: []ast.Expr{
+					ast.NewIdent("quote"),
+					&ast.DottedList{
+						List: []ast.Expr{
+							&ast.IntLit{Value: 1},
+							&ast.DottedList{
+								List: []ast.Expr{ast.NewIdent("a")},
+								Tail: ast.NewIdent("b"),
+							},
+						},
+						Tail: &ast.ListExpr{
+							List: []ast.Expr{ast.NewIdent("c")},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range testData {
@@ -70,9 +136,118 @@ func Test_next(t *testing.T) {
 			t.Error(err)
 			break
 		}
+		clearSpan(result)
 
 		if !reflect.DeepEqual(result, test.result) {
 			t.Errorf("\ninput: '%s'\nexpect: %s\noutput: %s", test.input, test.result, result)
 		}
 	}
 }
+
+// clearSpan drops the source ranges from expr so that it can be compared with
+// hand-built expressions.
+func clearSpan(expr ast.Expr) {
+	ast.SetSpan(expr, nil, nil)
+	switch expr := expr.(type) {
+	case *ast.ListExpr:
+		for _, item := range expr.List {
+			clearSpan(item)
+		}
+	case *ast.VectorLit:
+		for _, item := range expr.List {
+			clearSpan(item)
+		}
+	case *ast.DottedList:
+		for _, item := range expr.List {
+			clearSpan(item)
+		}
+		clearSpan(expr.Tail)
+	}
+}
+
+func Test_nextSpan(t *testing.T) {
+	input := "(define x\n  '(12 abc))"
+	p := NewParser(token.NewLexer(strings.NewReader(input)))
+	result, err := p.next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list := result.(*ast.ListExpr)
+	quoted := list.List[2].(*ast.ListExpr)
+	inner := quoted.List[1].(*ast.ListExpr)
+	testData := []struct {
+		expr     ast.Expr
+		pos, end ast.Pos
+	}{
+		{list, ast.NewPos(1, 1), ast.NewPos(2, 13)},
+		{list.List[0], ast.NewPos(1, 2), ast.NewPos(1, 8)},
+		{list.List[1], ast.NewPos(1, 9), ast.NewPos(1, 10)},
+		{quoted, ast.NewPos(2, 3), ast.NewPos(2, 12)},
+		{quoted.List[0], ast.NewPos(2, 3), ast.NewPos(2, 4)},
+		{inner, ast.NewPos(2, 4), ast.NewPos(2, 12)},
+		{inner.List[0], ast.NewPos(2, 5), ast.NewPos(2, 7)},
+		{inner.List[1], ast.NewPos(2, 8), ast.NewPos(2, 11)},
+	}
+	for _, test := range testData {
+		if *test.expr.Pos() != test.pos || *test.expr.End() != test.end {
+			t.Errorf("\nexpr: %s\nexpect: %v-%v\noutput: %v-%v", test.expr, test.pos, test.end, *test.expr.Pos(), *test.expr.End())
+		}
+	}
+}
+
+func Test_nextBadByte(t *testing.T) {
+	p := NewParser(token.NewLexer(strings.NewReader("#u8(1\n 256)")))
+	_, err := p.next()
+	e, ok := err.(*ast.Error)
+	if !ok {
+		t.Fatalf("expect a located error, output: %v", err)
+	}
+	if e.Phase != ast.ParsePhase || *e.From != ast.NewPos(2, 2) || *e.To != ast.NewPos(2, 5) {
+		t.Errorf("expect: 2:2-2:5 parse error, output: %v-%v %s", e.From, e.To, e)
+	}
+}
+
+func Test_nextBadDot(t *testing.T) {
+	testData := []string{
+		"(. a)",
+		"(a .)",
+		"(a . b c)",
+		"(a . b . c)",
+		"#(a . b)",
+		"#u8(1 . 2)",
+		". a",
+	}
+	for _, input := range testData {
+		p := NewParser(token.NewLexer(strings.NewReader(input)))
+		if result, err := p.next(); err == nil {
+			t.Errorf("\ninput: '%s'\nexpect an error\noutput: %s", input, result)
+		}
+	}
+}
+
+func Test_nextUnclosed(t *testing.T) {
+	testData := []struct {
+		input string
+		pos   ast.Pos
+	}{
+		{"(+ 1 2", ast.NewPos(1, 1)},
+		{"(a\n  (b c)", ast.NewPos(1, 1)},
+		{"(a\n  (b c", ast.NewPos(2, 3)},
+		{"(a . b", ast.NewPos(1, 1)},
+		{"'#(1 2", ast.NewPos(1, 2)},
+		{"#u8(1", ast.NewPos(1, 1)},
+	}
+	for _, test := range testData {
+		p := NewParser(token.NewLexer(strings.NewReader(test.input)))
+		result, err := p.next()
+		e, ok := err.(*ast.Error)
+		if !ok {
+			t.Errorf("\ninput: '%s'\nexpect a located error\noutput: %v %v", test.input, result, err)
+			continue
+		}
+		if e.Phase != ast.ParsePhase || *e.From != test.pos {
+			t.Errorf("\ninput: '%s'\nexpect: %v parse error\noutput: %v %s", test.input, test.pos, e.From, e)
+		}
+	}
+}
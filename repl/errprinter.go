@@ -1,16 +1,95 @@
 package repl
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+
+	"github.com/dyzsr/mylisp/ast"
+	"github.com/dyzsr/mylisp/runtime"
 )
 
-type ErrorPrinter struct{}
+// Source gives access to the lines of a source file.
+type Source interface {
+	Line(int) (string, bool)
+}
+
+type ErrorPrinter struct {
+	sources map[string]Source
+}
 
 func NewErrorPrinter() *ErrorPrinter {
-	return &ErrorPrinter{}
+	return &ErrorPrinter{
+		sources: make(map[string]Source),
+	}
+}
+
+// AddSource registers the source of the named file, so that errors located
+// in it can be shown with an excerpt.
+func (p *ErrorPrinter) AddSource(filename string, src Source) {
+	p.sources[filename] = src
 }
 
 func (p *ErrorPrinter) Print(err error) {
-	fmt.Fprintf(os.Stderr, "~ %s\n", err)
+	p.fprint(os.Stderr, err)
+}
+
+func (p *ErrorPrinter) fprint(w io.Writer, err error) {
+	fmt.Fprintf(w, "~ %s\n", err)
+	p.fprintExcerpt(w, err)
+
+	var e *runtime.TraceError
+	if errors.As(err, &e) {
+		fmt.Fprintf(w, "  traceback (most recent call last):\n")
+		for _, line := range e.Traceback() {
+			fmt.Fprintf(w, "    %s\n", line)
+		}
+	}
+}
+
+func (p *ErrorPrinter) fprintExcerpt(w io.Writer, err error) {
+	var e *ast.Error
+	if !errors.As(err, &e) || e.From == nil {
+		return
+	}
+	src, ok := p.sources[e.From.Filename]
+	if !ok {
+		return
+	}
+	line, ok := src.Line(e.From.Line)
+	if !ok {
+		return
+	}
+	fmt.Fprintf(w, "    %s\n    %s\n", line, underline(line, e.From, e.To))
+}
+
+// underline marks the columns from..to of line with carets. A range which
+// spans several lines is marked up to the end of the first one.
+func underline(line string, from, to *ast.Pos) string {
+	runes := []rune(line)
+	begin := from.Column - 1
+	end := len(runes)
+	if to != nil && to.Line == from.Line && to.Column-1 < end {
+		end = to.Column - 1
+	}
+	if begin > end {
+		begin = end
+	}
+
+	var sb strings.Builder
+	for _, ch := range runes[:begin] {
+		// keep tabs so that the carets line up with the source
+		if ch == '\t' {
+			sb.WriteRune('\t')
+		} else {
+			sb.WriteRune(' ')
+		}
+	}
+	sb.WriteString("^")
+	if end-begin > 1 {
+		sb.WriteString(strings.Repeat("~", end-begin-1))
+	}
+	return sb.String()
 }
@@ -0,0 +1,59 @@
+package repl
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dyzsr/mylisp/ast"
+)
+
+type testSource []string
+
+func (s testSource) Line(line int) (string, bool) {
+	if line < 1 || line > len(s) {
+		return "", false
+	}
+	return s[line-1], true
+}
+
+func TestErrorPrinter(t *testing.T) {
+	pos := func(line, column int) *ast.Pos {
+		p := ast.NewPos(line, column)
+		p.Filename = "a.scm"
+		return &p
+	}
+
+	testData := []struct {
+		err    error
+		result string
+	}{
+		{
+			err:    errors.New("plain"),
+			result: "~ plain\n",
+		},
+		{
+			err: &ast.Error{Phase: ast.RuntimePhase, From: pos(2, 2), To: pos(2, 9), Err: errors.New("oops")},
+			result: "~ a.scm:2:2: runtime error: oops\n" +
+				"    \t(car x))\n" +
+				"    \t^~~~~~~\n",
+		},
+		{
+			err: &ast.Error{Phase: ast.ParsePhase, From: pos(1, 1), To: pos(2, 2), Err: errors.New("oops")},
+			result: "~ a.scm:1:1: parse error: oops\n" +
+				"    (define f\n" +
+				"    ^~~~~~~~~\n",
+		},
+	}
+
+	p := NewErrorPrinter()
+	p.AddSource("a.scm", testSource{"(define f", "\t(car x))"})
+	for _, test := range testData {
+		var buf bytes.Buffer
+		p.fprint(&buf, test.err)
+		if result := buf.String(); result != test.result {
+			t.Errorf("\nexpect:\n%s\noutput:\n%s", strings.TrimSpace(test.result), strings.TrimSpace(result))
+		}
+	}
+}
@@ -2,280 +2,284 @@ package runtime
 
 import (
 	"errors"
-	"reflect"
 )
 
 var (
-	builtinAdd   = &BuiltinProc{name: "+", proc: _add}
-	builtinSub   = &BuiltinProc{name: "-", proc: _sub}
-	builtinMul   = &BuiltinProc{name: "*", proc: _mul}
-	builtinDiv   = &BuiltinProc{name: "/", proc: _div}
-	builtinMod   = &BuiltinProc{name: "mod", proc: _mod}
-	builtinEqNum = &BuiltinProc{name: "=", proc: _eqNum}
-	builtinLt    = &BuiltinProc{name: "<", proc: _lt}
-	builtinLte   = &BuiltinProc{name: "<=", proc: _lte}
-	builtinGt    = &BuiltinProc{name: ">", proc: _gt}
-	builtinGte   = &BuiltinProc{name: ">=", proc: _gte}
-	builtinAnd   = &BuiltinProc{name: "and", proc: _and}
-	builtinOr    = &BuiltinProc{name: "or", proc: _or}
-	builtinNot   = &BuiltinProc{name: "not", proc: _not}
-	builtinCons  = &BuiltinProc{name: "cons", proc: _cons}
-	builtinCar   = &BuiltinProc{name: "car", proc: _car}
-	builtinCdr   = &BuiltinProc{name: "cdr", proc: _cdr}
-	builtinList  = &BuiltinProc{name: "list", proc: _list}
-	builtinEq    = &BuiltinProc{name: "eq?", proc: _eq}
-	builtinEqual = &BuiltinProc{name: "equal?", proc: _equal}
+	builtinAdd    = &BuiltinProc{name: "+", proc: _add, arity: atLeast(0)}
+	builtinSub    = &BuiltinProc{name: "-", proc: _sub, arity: atLeast(1)}
+	builtinMul    = &BuiltinProc{name: "*", proc: _mul, arity: atLeast(0)}
+	builtinDiv    = &BuiltinProc{name: "/", proc: _div, arity: atLeast(2)}
+	builtinMod    = &BuiltinProc{name: "mod", proc: _mod, arity: fixed(2)}
+	builtinEqNum  = &BuiltinProc{name: "=", proc: _eqNum, arity: atLeast(1)}
+	builtinLt     = &BuiltinProc{name: "<", proc: _lt, arity: atLeast(1)}
+	builtinLte    = &BuiltinProc{name: "<=", proc: _lte, arity: atLeast(1)}
+	builtinGt     = &BuiltinProc{name: ">", proc: _gt, arity: atLeast(1)}
+	builtinGte    = &BuiltinProc{name: ">=", proc: _gte, arity: atLeast(1)}
+	builtinNot    = &BuiltinProc{name: "not", proc: _not, arity: fixed(1)}
+	builtinCons   = &BuiltinProc{name: "cons", proc: _cons, arity: fixed(2)}
+	builtinCar    = &BuiltinProc{name: "car", proc: _car, arity: fixed(1)}
+	builtinCdr    = &BuiltinProc{name: "cdr", proc: _cdr, arity: fixed(1)}
+	builtinSetCar = &BuiltinProc{name: "set-car!", proc: _setCar, arity: fixed(2)}
+	builtinSetCdr = &BuiltinProc{name: "set-cdr!", proc: _setCdr, arity: fixed(2)}
+	builtinList   = &BuiltinProc{name: "list", proc: _list, arity: atLeast(0)}
+	builtinEq     = &BuiltinProc{name: "eq?", proc: _eq, arity: fixed(2)}
+	builtinEqv    = &BuiltinProc{name: "eqv?", proc: _eqv, arity: fixed(2)}
+	builtinEqual  = &BuiltinProc{name: "equal?", proc: _equal, arity: fixed(2)}
+	builtinMemq   = &BuiltinProc{name: "memq", proc: memberProc(eq), arity: fixed(2)}
+	builtinMemv   = &BuiltinProc{name: "memv", proc: memberProc(eqv), arity: fixed(2)}
+	builtinMember = &BuiltinProc{name: "member", call: _member, arity: between(2, 3)}
+	builtinAssq   = &BuiltinProc{name: "assq", proc: assocProc(eq), arity: fixed(2)}
+	builtinAssv   = &BuiltinProc{name: "assv", proc: assocProc(eqv), arity: fixed(2)}
+	builtinAssoc  = &BuiltinProc{name: "assoc", call: _assoc, arity: between(2, 3)}
 )
 
 func builtinVariables() map[string]Value {
 	return map[string]Value{
-		"+":      builtinAdd,
-		"-":      builtinSub,
-		"*":      builtinMul,
-		"/":      builtinDiv,
-		"mod":    builtinMod,
-		"=":      builtinEqNum,
-		"<":      builtinLt,
-		"<=":     builtinLte,
-		">":      builtinGt,
-		">=":     builtinGte,
-		"and":    builtinAnd,
-		"or":     builtinOr,
-		"not":    builtinNot,
-		"cons":   builtinCons,
-		"car":    builtinCar,
-		"cdr":    builtinCdr,
-		"list":   builtinList,
-		"eq?":    builtinEq,
-		"equal?": builtinEqual,
-		"nil":    Nil{},
+		"+":        builtinAdd,
+		"-":        builtinSub,
+		"*":        builtinMul,
+		"/":        builtinDiv,
+		"mod":      builtinMod,
+		"=":        builtinEqNum,
+		"<":        builtinLt,
+		"<=":       builtinLte,
+		">":        builtinGt,
+		">=":       builtinGte,
+		"not":      builtinNot,
+		"cons":     builtinCons,
+		"car":      builtinCar,
+		"cdr":      builtinCdr,
+		"set-car!": builtinSetCar,
+		"set-cdr!": builtinSetCdr,
+		"list":     builtinList,
+		"eq?":      builtinEq,
+		"eqv?":     builtinEqv,
+		"equal?":   builtinEqual,
+		"memq":     builtinMemq,
+		"memv":     builtinMemv,
+		"member":   builtinMember,
+		"assq":     builtinAssq,
+		"assv":     builtinAssv,
+		"assoc":    builtinAssoc,
+		"nil":      Nil{},
+
+		"apply":      builtinApply,
+		"map":        builtinMap,
+		"for-each":   builtinForEach,
+		"filter":     builtinFilter,
+		"reduce":     builtinReduce,
+		"fold-left":  builtinFoldLeft,
+		"fold-right": builtinFoldRight,
+		"sort":       builtinSort,
+		"append":     builtinAppend,
+		"reverse":    builtinReverse,
+		"length":     builtinLength,
+		"list-ref":   builtinListRef,
+		"list-tail":  builtinListTail,
+		"last-pair":  builtinLastPair,
+		"list-copy":  builtinListCopy,
+		"iota":       builtinIota,
+
+		"null?":           builtinIsNull,
+		"pair?":           builtinIsPair,
+		"list?":           builtinIsList,
+		"symbol?":         builtinIsSymbol,
+		"number?":         builtinIsNumber,
+		"integer?":        builtinIsInteger,
+		"rational?":       builtinIsRational,
+		"boolean?":        builtinIsBoolean,
+		"procedure?":      builtinIsProcedure,
+		"type-of":         builtinTypeOf,
+		"procedure-arity": builtinProcArity,
+
+		"exact->inexact": builtinExactToInexact,
+		"inexact->exact": builtinInexactToExact,
+		"exact":          builtinInexactToExact,
+		"inexact":        builtinExactToInexact,
+		"floor":          builtinFloor,
+		"ceiling":        builtinCeiling,
+		"round":          builtinRound,
+		"truncate":       builtinTruncate,
+		"sqrt":           builtinSqrt,
+		"expt":           builtinExpt,
+		"exp":            builtinExp,
+		"log":            builtinLog,
+		"sin":            builtinSin,
+		"cos":            builtinCos,
+		"atan":           builtinAtan,
+		"numerator":      builtinNumerator,
+		"denominator":    builtinDenominator,
+		"exact?":         builtinIsExact,
+		"inexact?":       builtinIsInexact,
+
+		"string?":        builtinIsString,
+		"string-length":  builtinStringLength,
+		"string-append":  builtinStringAppend,
+		"substring":      builtinSubstring,
+		"string-ref":     builtinStringRef,
+		"string=?":       builtinStringEq,
+		"string<?":       builtinStringLt,
+		"string->symbol": builtinStringToSymbol,
+		"symbol->string": builtinSymbolToString,
+		"number->string": builtinNumberToString,
+		"string->number": builtinStringToNumber,
+		"string-split":   builtinStringSplit,
+		"string-join":    builtinStringJoin,
+		"string->list":   builtinStringToList,
+		"list->string":   builtinListToString,
+
+		"char?":            builtinIsChar,
+		"char->integer":    builtinCharToInteger,
+		"integer->char":    builtinIntegerToChar,
+		"char-upcase":      builtinCharUpcase,
+		"char-downcase":    builtinCharDowncase,
+		"char-alphabetic?": builtinIsCharAlphabetic,
+		"char-numeric?":    builtinIsCharNumeric,
+		"char-whitespace?": builtinIsCharWhitespace,
+
+		"vector?":         builtinIsVector,
+		"make-vector":     builtinMakeVector,
+		"vector":          builtinVector,
+		"vector-length":   builtinVectorLength,
+		"vector-ref":      builtinVectorRef,
+		"vector-set!":     builtinVectorSet,
+		"vector->list":    builtinVectorToList,
+		"list->vector":    builtinListToVector,
+		"vector-fill!":    builtinVectorFill,
+		"vector-copy":     builtinVectorCopy,
+		"vector-map":      builtinVectorMap,
+		"vector-for-each": builtinVectorForEach,
+
+		"bytevector?":          builtinIsBytevector,
+		"make-bytevector":      builtinMakeBytevector,
+		"bytevector":           builtinBytevector,
+		"bytevector-length":    builtinBytevectorLength,
+		"bytevector-u8-ref":    builtinBytevectorU8Ref,
+		"bytevector-u8-set!":   builtinBytevectorU8Set,
+		"bytevector-copy":      builtinBytevectorCopy,
+		"bytevector-append":    builtinBytevectorAppend,
+		"utf8->string":         builtinUtf8ToString,
+		"string->utf8":         builtinStringToUtf8,
+		"bytevector-uint-ref":  builtinBytevectorUintRef,
+		"bytevector-sint-ref":  builtinBytevectorSintRef,
+		"bytevector-uint-set!": builtinBytevectorUintSet,
+		"bytevector-sint-set!": builtinBytevectorSintSet,
+
+		"hash-table?":            builtinIsHashTable,
+		"make-hash-table":        builtinMakeHashTable,
+		"hash-table-ref":         builtinHashTableRef,
+		"hash-table-ref/default": builtinHashTableRefDefault,
+		"hash-table-set!":        builtinHashTableSet,
+		"hash-table-delete!":     builtinHashTableDelete,
+		"hash-table-contains?":   builtinHashTableContains,
+		"hash-table-count":       builtinHashTableCount,
+		"hash-table-keys":        builtinHashTableKeys,
+		"hash-table-values":      builtinHashTableValues,
+		"hash-table->alist":      builtinHashTableToAlist,
+		"hash-table-update!":     builtinHashTableUpdate,
+
+		// the procedures called by the expansion of quasiquote, under names
+		// which can not be read, so that no variable shadows them
+		"#cons":         builtinCons,
+		"#list":         builtinList,
+		"#append":       builtinAppend,
+		"#list->vector": builtinListToVector,
 	}
 }
 
 var (
-	errTypeMismatch  = errors.New("operand types mismatch")
-	errArityMismatch = errors.New("arity mismatch")
+	errTypeMismatch   = errors.New("operand types mismatch")
+	errArityMismatch  = errors.New("arity mismatch")
+	errDivisionByZero = errors.New("division by zero")
+	errNoExact        = errors.New("no exact representation")
+	errNotProcedure   = errors.New("not a procedure")
 )
 
-func toInts(args []Value) ([]Int, error) {
-	var nums []Int
-	for _, arg := range args {
-		num, ok := arg.(Int)
-		if !ok {
-			return nil, errTypeMismatch
-		}
-		nums = append(nums, num)
-	}
-	return nums, nil
-}
-
-func toBools(args []Value) ([]Bool, error) {
-	var bools []Bool
-	for _, arg := range args {
-		bol, ok := arg.(Bool)
-		if !ok {
-			return nil, errTypeMismatch
-		}
-		bools = append(bools, bol)
-	}
-	return bools, nil
+// isTrue reports whether a value counts as true in a condition, which is
+// any value but false.
+func isTrue(v Value) bool {
+	b, ok := v.(Bool)
+	return !ok || bool(b)
 }
 
 func _add(args ...Value) (Value, error) {
-	nums, err := toInts(args)
-	if err != nil {
+	if err := checkNumbers(args); err != nil {
 		return nil, err
 	}
-	var result Int
-	for _, num := range nums {
-		result += num
-	}
-	return result, nil
+	return arithAdd.fold(Int(0), args)
 }
 
 func _sub(args ...Value) (Value, error) {
 	if len(args) == 0 {
 		return nil, errArityMismatch
 	}
-	nums, err := toInts(args)
-	if err != nil {
+	if err := checkNumbers(args); err != nil {
 		return nil, err
 	}
 
 	// negation
-	minuend := nums[0]
-	if len(nums) == 1 {
-		return -minuend, nil
+	minuend := args[0]
+	if len(args) == 1 {
+		return arithMul.apply(Int(-1), minuend)
 	}
 
 	// subtraction
-	result := minuend
-	for _, num := range nums[1:] {
-		result -= num
-	}
-	return result, nil
+	return arithSub.fold(minuend, args[1:])
 }
 
 func _mul(args ...Value) (Value, error) {
-	nums, err := toInts(args)
-	if err != nil {
+	if err := checkNumbers(args); err != nil {
 		return nil, err
 	}
-	var result Int = 1
-	for _, num := range nums {
-		result *= num
-	}
-	return result, nil
+	return arithMul.fold(Int(1), args)
 }
 
 func _div(args ...Value) (Value, error) {
 	if len(args) < 2 {
 		return nil, errArityMismatch
 	}
-	nums, err := toInts(args)
-	if err != nil {
+	if err := checkNumbers(args); err != nil {
 		return nil, err
 	}
-
-	result := nums[0]
-	for _, num := range nums[1:] {
-		result /= num
-	}
-	return result, nil
+	return arithDiv.fold(args[0], args[1:])
 }
 
 func _mod(args ...Value) (Value, error) {
 	if len(args) != 2 {
 		return nil, errArityMismatch
 	}
-	nums, err := toInts(args)
-	if err != nil {
+	if err := checkNumbers(args); err != nil {
 		return nil, err
 	}
-	result := nums[0] % nums[1]
-	return result, nil
+	return arithMod.apply(args[0], args[1])
 }
 
 func _eqNum(args ...Value) (Value, error) {
-	if len(args) == 0 {
-		return nil, errArityMismatch
-	}
-	nums, err := toInts(args)
-	if err != nil {
-		return nil, err
-	}
-
-	for i := 1; i < len(nums); i++ {
-		if nums[i-1] != nums[i] {
-			return Bool(false), nil
-		}
-	}
-	return Bool(true), nil
+	return compareChain(args, func(cmp int) bool { return cmp == 0 })
 }
 
 func _lt(args ...Value) (Value, error) {
-	if len(args) == 0 {
-		return nil, errArityMismatch
-	}
-	nums, err := toInts(args)
-	if err != nil {
-		return nil, err
-	}
-
-	for i := 1; i < len(nums); i++ {
-		if nums[i-1] >= nums[i] {
-			return Bool(false), nil
-		}
-	}
-	return Bool(true), nil
+	return compareChain(args, func(cmp int) bool { return cmp < 0 })
 }
 
 func _lte(args ...Value) (Value, error) {
-	if len(args) == 0 {
-		return nil, errArityMismatch
-	}
-	nums, err := toInts(args)
-	if err != nil {
-		return nil, err
-	}
-
-	for i := 1; i < len(nums); i++ {
-		if nums[i-1] > nums[i] {
-			return Bool(false), nil
-		}
-	}
-	return Bool(true), nil
+	return compareChain(args, func(cmp int) bool { return cmp <= 0 })
 }
 
 func _gt(args ...Value) (Value, error) {
-	if len(args) == 0 {
-		return nil, errArityMismatch
-	}
-	nums, err := toInts(args)
-	if err != nil {
-		return nil, err
-	}
-
-	for i := 1; i < len(nums); i++ {
-		if nums[i-1] <= nums[i] {
-			return Bool(false), nil
-		}
-	}
-	return Bool(true), nil
+	return compareChain(args, func(cmp int) bool { return cmp > 0 })
 }
 
 func _gte(args ...Value) (Value, error) {
-	if len(args) == 0 {
-		return nil, errArityMismatch
-	}
-	nums, err := toInts(args)
-	if err != nil {
-		return nil, err
-	}
-
-	for i := 1; i < len(nums); i++ {
-		if nums[i-1] < nums[i] {
-			return Bool(false), nil
-		}
-	}
-	return Bool(true), nil
-}
-
-func _and(args ...Value) (Value, error) {
-	bools, err := toBools(args)
-	if err != nil {
-		return nil, err
-	}
-	for _, bol := range bools {
-		if !bol {
-			return Bool(false), nil
-		}
-	}
-	return Bool(true), nil
-}
-
-func _or(args ...Value) (Value, error) {
-	bools, err := toBools(args)
-	if err != nil {
-		return nil, err
-	}
-	for _, bol := range bools {
-		if bol {
-			return Bool(true), nil
-		}
-	}
-	return Bool(false), nil
+	return compareChain(args, func(cmp int) bool { return cmp >= 0 })
 }
 
+// _not is true only for false.
 func _not(args ...Value) (Value, error) {
 	if len(args) != 1 {
 		return nil, errArityMismatch
 	}
-	bools, err := toBools(args)
-	if err != nil {
-		return nil, err
-	}
-	return !bools[0], nil
+	return Bool(!isTrue(args[0])), nil
 }
 
 func _cons(args ...Value) (Value, error) {
@@ -307,6 +311,30 @@ func _cdr(args ...Value) (Value, error) {
 	return p.second, nil
 }
 
+func _setCar(args ...Value) (Value, error) {
+	if len(args) != 2 {
+		return nil, errArityMismatch
+	}
+	p, ok := args[0].(*Pair)
+	if !ok {
+		return nil, errTypeMismatch
+	}
+	p.first = args[1]
+	return Nil{}, nil
+}
+
+func _setCdr(args ...Value) (Value, error) {
+	if len(args) != 2 {
+		return nil, errArityMismatch
+	}
+	p, ok := args[0].(*Pair)
+	if !ok {
+		return nil, errTypeMismatch
+	}
+	p.second = args[1]
+	return Nil{}, nil
+}
+
 func _list(args ...Value) (Value, error) {
 	var result Value = Nil{}
 	for i := len(args) - 1; i >= 0; i-- {
@@ -323,12 +351,144 @@ func _eq(args ...Value) (Value, error) {
 	if len(args) != 2 {
 		return nil, errArityMismatch
 	}
-	return Bool(args[0] == args[1]), nil
+	return Bool(eq(args[0], args[1])), nil
+}
+
+func _eqv(args ...Value) (Value, error) {
+	if len(args) != 2 {
+		return nil, errArityMismatch
+	}
+	return Bool(eqv(args[0], args[1])), nil
 }
 
 func _equal(args ...Value) (Value, error) {
 	if len(args) != 2 {
 		return nil, errArityMismatch
 	}
-	return Bool(reflect.DeepEqual(args[0], args[1])), nil
+	return Bool(equal(args[0], args[1])), nil
+}
+
+// listToSlice collects the items of a proper list.
+func listToSlice(list Value) ([]Value, error) {
+	var items []Value
+	for {
+		switch v := list.(type) {
+		case Nil:
+			return items, nil
+		case *Pair:
+			items = append(items, v.first)
+			list = v.second
+		default:
+			return nil, errTypeMismatch
+		}
+	}
+}
+
+// sameFunc compares two values, and may fail if it calls a procedure.
+type sameFunc func(a, b Value) (bool, error)
+
+// sameAs makes a sameFunc of a comparison which never fails.
+func sameAs(same func(a, b Value) bool) sameFunc {
+	return func(a, b Value) (bool, error) {
+		return same(a, b), nil
+	}
+}
+
+// sameBy makes a sameFunc which calls a procedure to compare.
+func sameBy(r *Runtime, proc Value) sameFunc {
+	return func(a, b Value) (bool, error) {
+		result, err := r.apply(proc, a, b)
+		if err != nil {
+			return false, err
+		}
+		return isTrue(result), nil
+	}
+}
+
+// member returns the first sublist of a list whose first item is the same as
+// x, or false if there is none.
+func member(x, list Value, same sameFunc) (Value, error) {
+	for {
+		switch p := list.(type) {
+		case Nil:
+			return Bool(false), nil
+		case *Pair:
+			ok, err := same(x, p.first)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				return p, nil
+			}
+			list = p.second
+		default:
+			return nil, errTypeMismatch
+		}
+	}
+}
+
+// assoc returns the first pair of an association list whose key is the same
+// as x, or false if there is none.
+func assoc(x, list Value, same sameFunc) (Value, error) {
+	for {
+		switch p := list.(type) {
+		case Nil:
+			return Bool(false), nil
+		case *Pair:
+			entry, ok := p.first.(*Pair)
+			if !ok {
+				return nil, errTypeMismatch
+			}
+			if ok, err := same(x, entry.first); err != nil {
+				return nil, err
+			} else if ok {
+				return entry, nil
+			}
+			list = p.second
+		default:
+			return nil, errTypeMismatch
+		}
+	}
+}
+
+func memberProc(same func(a, b Value) bool) func(...Value) (Value, error) {
+	return func(args ...Value) (Value, error) {
+		if len(args) != 2 {
+			return nil, errArityMismatch
+		}
+		return member(args[0], args[1], sameAs(same))
+	}
+}
+
+func assocProc(same func(a, b Value) bool) func(...Value) (Value, error) {
+	return func(args ...Value) (Value, error) {
+		if len(args) != 2 {
+			return nil, errArityMismatch
+		}
+		return assoc(args[0], args[1], sameAs(same))
+	}
+}
+
+// _member compares with equal?, or with the optional compare procedure.
+func _member(r *Runtime, args ...Value) (Value, error) {
+	if len(args) != 2 && len(args) != 3 {
+		return nil, errArityMismatch
+	}
+	same := sameAs(equal)
+	if len(args) == 3 {
+		same = sameBy(r, args[2])
+	}
+	return member(args[0], args[1], same)
+}
+
+// _assoc compares with equal?, or with the optional compare procedure.
+func _assoc(r *Runtime, args ...Value) (Value, error) {
+	if len(args) != 2 && len(args) != 3 {
+		return nil, errArityMismatch
+	}
+	same := sameAs(equal)
+	if len(args) == 3 {
+		same = sameBy(r, args[2])
+	}
+	return assoc(args[0], args[1], same)
 }
@@ -0,0 +1,315 @@
+package runtime
+
+import (
+	"errors"
+	"math/big"
+	"unicode/utf8"
+)
+
+var (
+	builtinIsBytevector      = &BuiltinProc{name: "bytevector?", proc: _isBytevector, arity: fixed(1)}
+	builtinMakeBytevector    = &BuiltinProc{name: "make-bytevector", proc: _makeBytevector, arity: between(1, 2)}
+	builtinBytevector        = &BuiltinProc{name: "bytevector", proc: _bytevector, arity: atLeast(0)}
+	builtinBytevectorLength  = &BuiltinProc{name: "bytevector-length", proc: _bytevectorLength, arity: fixed(1)}
+	builtinBytevectorU8Ref   = &BuiltinProc{name: "bytevector-u8-ref", proc: _bytevectorU8Ref, arity: fixed(2)}
+	builtinBytevectorU8Set   = &BuiltinProc{name: "bytevector-u8-set!", proc: _bytevectorU8Set, arity: fixed(3)}
+	builtinBytevectorCopy    = &BuiltinProc{name: "bytevector-copy", proc: _bytevectorCopy, arity: between(1, 3)}
+	builtinBytevectorAppend  = &BuiltinProc{name: "bytevector-append", proc: _bytevectorAppend, arity: atLeast(0)}
+	builtinUtf8ToString      = &BuiltinProc{name: "utf8->string", proc: _utf8ToString, arity: between(1, 3)}
+	builtinStringToUtf8      = &BuiltinProc{name: "string->utf8", proc: _stringToUtf8, arity: between(1, 3)}
+	builtinBytevectorUintRef = &BuiltinProc{name: "bytevector-uint-ref", proc: intRefProc(false), arity: fixed(4)}
+	builtinBytevectorSintRef = &BuiltinProc{name: "bytevector-sint-ref", proc: intRefProc(true), arity: fixed(4)}
+	builtinBytevectorUintSet = &BuiltinProc{name: "bytevector-uint-set!", proc: intSetProc(false), arity: fixed(5)}
+	builtinBytevectorSintSet = &BuiltinProc{name: "bytevector-sint-set!", proc: intSetProc(true), arity: fixed(5)}
+)
+
+var (
+	errBadByte       = errors.New("byte out of range")
+	errBadUTF8       = errors.New("bad UTF-8 sequence")
+	errBadEndianness = errors.New("bad endianness")
+	errIntOutOfRange = errors.New("integer out of range")
+)
+
+func toBytevectors(args []Value) ([]*Bytevector, error) {
+	var bvs []*Bytevector
+	for _, arg := range args {
+		bv, ok := arg.(*Bytevector)
+		if !ok {
+			return nil, errTypeMismatch
+		}
+		bvs = append(bvs, bv)
+	}
+	return bvs, nil
+}
+
+func toByte(arg Value) (byte, error) {
+	if _, ok := arg.(BigInt); ok {
+		return 0, errBadByte
+	}
+	n, ok := arg.(Int)
+	if !ok {
+		return 0, errTypeMismatch
+	}
+	if n < 0 || n > 255 {
+		return 0, errBadByte
+	}
+	return byte(n), nil
+}
+
+func _isBytevector(args ...Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, errArityMismatch
+	}
+	_, ok := args[0].(*Bytevector)
+	return Bool(ok), nil
+}
+
+// _makeBytevector makes a bytevector of k bytes, which are all fill if given
+// or zero otherwise.
+func _makeBytevector(args ...Value) (Value, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, errArityMismatch
+	}
+	k, err := toLength(args[0])
+	if err != nil {
+		return nil, err
+	}
+	var fill byte
+	if len(args) == 2 {
+		if fill, err = toByte(args[1]); err != nil {
+			return nil, err
+		}
+	}
+	bytes := make([]byte, k)
+	for i := range bytes {
+		bytes[i] = fill
+	}
+	return &Bytevector{bytes: bytes}, nil
+}
+
+func _bytevector(args ...Value) (Value, error) {
+	bytes := make([]byte, len(args))
+	for i, arg := range args {
+		var err error
+		if bytes[i], err = toByte(arg); err != nil {
+			return nil, err
+		}
+	}
+	return &Bytevector{bytes: bytes}, nil
+}
+
+func _bytevectorLength(args ...Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, errArityMismatch
+	}
+	bv, ok := args[0].(*Bytevector)
+	if !ok {
+		return nil, errTypeMismatch
+	}
+	return Int(len(bv.bytes)), nil
+}
+
+func _bytevectorU8Ref(args ...Value) (Value, error) {
+	if len(args) != 2 {
+		return nil, errArityMismatch
+	}
+	bv, ok := args[0].(*Bytevector)
+	if !ok {
+		return nil, errTypeMismatch
+	}
+	k, err := toIndex(args[1], len(bv.bytes)-1)
+	if err != nil {
+		return nil, err
+	}
+	return Int(bv.bytes[k]), nil
+}
+
+func _bytevectorU8Set(args ...Value) (Value, error) {
+	if len(args) != 3 {
+		return nil, errArityMismatch
+	}
+	bv, ok := args[0].(*Bytevector)
+	if !ok {
+		return nil, errTypeMismatch
+	}
+	k, err := toIndex(args[1], len(bv.bytes)-1)
+	if err != nil {
+		return nil, err
+	}
+	b, err := toByte(args[2])
+	if err != nil {
+		return nil, err
+	}
+	bv.bytes[k] = b
+	return Nil{}, nil
+}
+
+// _bytevectorCopy makes a new bytevector of the bytes from start to end.
+func _bytevectorCopy(args ...Value) (Value, error) {
+	if len(args) < 1 || len(args) > 3 {
+		return nil, errArityMismatch
+	}
+	bv, ok := args[0].(*Bytevector)
+	if !ok {
+		return nil, errTypeMismatch
+	}
+	start, end, err := toRange(args[1:], len(bv.bytes))
+	if err != nil {
+		return nil, err
+	}
+	return &Bytevector{bytes: append([]byte{}, bv.bytes[start:end]...)}, nil
+}
+
+func _bytevectorAppend(args ...Value) (Value, error) {
+	bvs, err := toBytevectors(args)
+	if err != nil {
+		return nil, err
+	}
+	bytes := []byte{}
+	for _, bv := range bvs {
+		bytes = append(bytes, bv.bytes...)
+	}
+	return &Bytevector{bytes: bytes}, nil
+}
+
+// _utf8ToString decodes the bytes from start to end as UTF-8.
+func _utf8ToString(args ...Value) (Value, error) {
+	if len(args) < 1 || len(args) > 3 {
+		return nil, errArityMismatch
+	}
+	bv, ok := args[0].(*Bytevector)
+	if !ok {
+		return nil, errTypeMismatch
+	}
+	start, end, err := toRange(args[1:], len(bv.bytes))
+	if err != nil {
+		return nil, err
+	}
+	bytes := bv.bytes[start:end]
+	if !utf8.Valid(bytes) {
+		return nil, errBadUTF8
+	}
+	return String(bytes), nil
+}
+
+// _stringToUtf8 encodes the characters from start to end as UTF-8.
+func _stringToUtf8(args ...Value) (Value, error) {
+	if len(args) < 1 || len(args) > 3 {
+		return nil, errArityMismatch
+	}
+	str, ok := args[0].(String)
+	if !ok {
+		return nil, errTypeMismatch
+	}
+	runes := []rune(string(str))
+	start, end, err := toRange(args[1:], len(runes))
+	if err != nil {
+		return nil, err
+	}
+	return &Bytevector{bytes: []byte(string(runes[start:end]))}, nil
+}
+
+// toIntField converts the bytevector, index, endianness and size arguments
+// which locate an integer of size bytes. It returns the bytes of the integer
+// and whether they are in big-endian order.
+func toIntField(bvArg, kArg, endiannessArg, sizeArg Value) ([]byte, bool, error) {
+	bv, ok := bvArg.(*Bytevector)
+	if !ok {
+		return nil, false, errTypeMismatch
+	}
+	var bigEndian bool
+	switch endiannessArg {
+	case Symbol{symbolMap("big")}:
+		bigEndian = true
+	case Symbol{symbolMap("little")}:
+		bigEndian = false
+	default:
+		return nil, false, errBadEndianness
+	}
+	size, ok := sizeArg.(Int)
+	if !ok {
+		return nil, false, errTypeMismatch
+	}
+	if size < 1 {
+		return nil, false, errIndexOutOfRange
+	}
+	k, err := toIndex(kArg, len(bv.bytes))
+	if err != nil {
+		return nil, false, err
+	}
+	if size > Int(len(bv.bytes)-k) {
+		return nil, false, errIndexOutOfRange
+	}
+	return bv.bytes[k : k+int(size)], bigEndian, nil
+}
+
+// intRefProc makes a procedure reading an unsigned or a signed integer from
+// a bytevector, as in (bytevector-uint-ref bv k 'big 4).
+func intRefProc(signed bool) func(...Value) (Value, error) {
+	return func(args ...Value) (Value, error) {
+		if len(args) != 4 {
+			return nil, errArityMismatch
+		}
+		field, bigEndian, err := toIntField(args[0], args[1], args[2], args[3])
+		if err != nil {
+			return nil, err
+		}
+		bytes := append([]byte{}, field...)
+		if !bigEndian {
+			reverseBytes(bytes)
+		}
+		n := new(big.Int).SetBytes(bytes)
+		if signed && bytes[0]&0x80 != 0 {
+			n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(8*len(bytes))))
+		}
+		return normalize(n), nil
+	}
+}
+
+// intSetProc makes a procedure writing an unsigned or a signed integer to a
+// bytevector, as in (bytevector-uint-set! bv k n 'big 4).
+func intSetProc(signed bool) func(...Value) (Value, error) {
+	return func(args ...Value) (Value, error) {
+		if len(args) != 5 {
+			return nil, errArityMismatch
+		}
+		field, bigEndian, err := toIntField(args[0], args[1], args[3], args[4])
+		if err != nil {
+			return nil, err
+		}
+		if !isExactInteger(args[2]) {
+			return nil, errTypeMismatch
+		}
+
+		n := new(big.Int).Set(toBig(args[2]))
+		bits := uint(8 * len(field))
+		limit := new(big.Int).Lsh(big.NewInt(1), bits)
+		if signed {
+			half := new(big.Int).Rsh(limit, 1)
+			if n.Cmp(half) >= 0 || n.Cmp(new(big.Int).Neg(half)) < 0 {
+				return nil, errIntOutOfRange
+			}
+			if n.Sign() < 0 {
+				n.Add(n, limit)
+			}
+		} else if n.Sign() < 0 || n.Cmp(limit) >= 0 {
+			return nil, errIntOutOfRange
+		}
+
+		bytes := n.Bytes()
+		for i := range field {
+			field[i] = 0
+		}
+		copy(field[len(field)-len(bytes):], bytes)
+		if !bigEndian {
+			reverseBytes(field)
+		}
+		return Nil{}, nil
+	}
+}
+
+func reverseBytes(bytes []byte) {
+	for i, j := 0, len(bytes)-1; i < j; i, j = i+1, j-1 {
+		bytes[i], bytes[j] = bytes[j], bytes[i]
+	}
+}
@@ -0,0 +1,99 @@
+package runtime
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_evalBytevectorBuiltinProc(t *testing.T) {
+	bv := func(bytes ...byte) *Bytevector {
+		return &Bytevector{bytes: append([]byte{}, bytes...)}
+	}
+	big, little := Symbol{symbolMap("big")}, Symbol{symbolMap("little")}
+
+	testData := []struct {
+		op       *BuiltinProc
+		operands []Value
+		result   Value
+	}{
+		{builtinIsBytevector, []Value{bv()}, Bool(true)},
+		{builtinIsBytevector, []Value{&Vector{}}, Bool(false)},
+		{builtinMakeBytevector, []Value{Int(3)}, bv(0, 0, 0)},
+		{builtinMakeBytevector, []Value{Int(2), Int(255)}, bv(255, 255)},
+		{builtinBytevector, []Value{Int(1), Int(2)}, bv(1, 2)},
+		{builtinBytevectorLength, []Value{bv(1, 2, 3)}, Int(3)},
+		{builtinBytevectorU8Ref, []Value{bv(1, 2, 3), Int(2)}, Int(3)},
+		{builtinBytevectorCopy, []Value{bv(1, 2, 3), Int(1), Int(2)}, bv(2)},
+		{builtinBytevectorAppend, []Value{bv(1), bv(), bv(2, 3)}, bv(1, 2, 3)},
+		{builtinBytevectorAppend, nil, bv()},
+		{builtinUtf8ToString, []Value{bv(0xce, 0xbb, 'x')}, String("λx")},
+		{builtinUtf8ToString, []Value{bv('a', 'b', 'c'), Int(1)}, String("bc")},
+		{builtinStringToUtf8, []Value{String("λx")}, bv(0xce, 0xbb, 'x')},
+		{builtinStringToUtf8, []Value{String("λxy"), Int(1), Int(2)}, bv('x')},
+		{builtinBytevectorUintRef, []Value{bv(1, 2, 3), Int(1), big, Int(2)}, Int(0x0203)},
+		{builtinBytevectorUintRef, []Value{bv(1, 2, 3), Int(1), little, Int(2)}, Int(0x0302)},
+		{builtinBytevectorUintRef, []Value{bv(0xff, 0xff), Int(0), big, Int(2)}, Int(0xffff)},
+		{builtinBytevectorSintRef, []Value{bv(0xff, 0xfe), Int(0), big, Int(2)}, Int(-2)},
+		{builtinBytevectorSintRef, []Value{bv(0xff, 0x7f), Int(0), little, Int(2)}, Int(0x7fff)},
+		{builtinBytevectorUintRef, []Value{bv(1, 0, 0, 0, 0, 0, 0, 0, 0), Int(0), big, Int(9)}, bigInt("18446744073709551616")},
+	}
+
+	r := NewRuntime()
+	for _, test := range testData {
+		result, err := r.evalBuiltinProc(test.op, test.operands...)
+		if err != nil {
+			t.Errorf("\nerror: %s\ninput: {%s, %s}", err, test.op, test.operands)
+			break
+		}
+		if !reflect.DeepEqual(result, test.result) {
+			t.Errorf("\ninput: {%s, %s}\nexpect: {%s}\noutput: {%s}", test.op, test.operands, test.result, result)
+		}
+	}
+
+	setData := []struct {
+		op       *BuiltinProc
+		operands []Value
+		result   *Bytevector
+	}{
+		{builtinBytevectorU8Set, []Value{Int(1), Int(9)}, bv(0, 9, 0, 0)},
+		{builtinBytevectorUintSet, []Value{Int(0), Int(0x010203), big, Int(3)}, bv(1, 2, 3, 0)},
+		{builtinBytevectorUintSet, []Value{Int(1), Int(0x010203), little, Int(3)}, bv(0, 3, 2, 1)},
+		{builtinBytevectorSintSet, []Value{Int(0), Int(-2), big, Int(4)}, bv(0xff, 0xff, 0xff, 0xfe)},
+		{builtinBytevectorSintSet, []Value{Int(2), Int(-128), little, Int(1)}, bv(0, 0, 0x80, 0)},
+	}
+	for _, test := range setData {
+		target := bv(0, 0, 0, 0)
+		if _, err := r.evalBuiltinProc(test.op, append([]Value{target}, test.operands...)...); err != nil {
+			t.Errorf("\nerror: %s\ninput: {%s, %s}", err, test.op, test.operands)
+			continue
+		}
+		if !reflect.DeepEqual(target, test.result) {
+			t.Errorf("\ninput: {%s, %s}\nexpect: {%s}\noutput: {%s}", test.op, test.operands, test.result, target)
+		}
+	}
+
+	errData := []struct {
+		op       *BuiltinProc
+		operands []Value
+		err      error
+	}{
+		{builtinMakeBytevector, []Value{Int(1), Int(256)}, errBadByte},
+		{builtinMakeBytevector, []Value{Int(100000000000000), Int(0)}, errTooLong},
+		{builtinMakeBytevector, []Value{bigInt("100000000000000000000")}, errTooLong},
+		{builtinBytevector, []Value{Int(-1)}, errBadByte},
+		{builtinBytevector, []Value{Char('a')}, errTypeMismatch},
+		{builtinBytevectorU8Ref, []Value{bv(1), Int(1)}, errIndexOutOfRange},
+		{builtinUtf8ToString, []Value{bv(0xce)}, errBadUTF8},
+		{builtinBytevectorUintRef, []Value{bv(1, 2), Int(1), big, Int(2)}, errIndexOutOfRange},
+		{builtinBytevectorUintRef, []Value{bv(1, 2), Int(0), Symbol{symbolMap("middle")}, Int(2)}, errBadEndianness},
+		{builtinBytevectorUintSet, []Value{bv(0), Int(0), Int(256), big, Int(1)}, errIntOutOfRange},
+		{builtinBytevectorUintSet, []Value{bv(0), Int(0), Int(-1), big, Int(1)}, errIntOutOfRange},
+		{builtinBytevectorSintSet, []Value{bv(0), Int(0), Int(128), big, Int(1)}, errIntOutOfRange},
+		{builtinBytevectorSintSet, []Value{bv(0), Int(0), Int(-129), big, Int(1)}, errIntOutOfRange},
+	}
+	for _, test := range errData {
+		if _, err := r.evalBuiltinProc(test.op, test.operands...); err != test.err {
+			t.Errorf("\ninput: {%s, %s}\nexpect: %v\noutput: %v", test.op, test.operands, test.err, err)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package runtime
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	builtinIsChar           = &BuiltinProc{name: "char?", proc: _isChar, arity: fixed(1)}
+	builtinCharToInteger    = &BuiltinProc{name: "char->integer", proc: _charToInteger, arity: fixed(1)}
+	builtinIntegerToChar    = &BuiltinProc{name: "integer->char", proc: _integerToChar, arity: fixed(1)}
+	builtinCharUpcase       = &BuiltinProc{name: "char-upcase", proc: _charUpcase, arity: fixed(1)}
+	builtinCharDowncase     = &BuiltinProc{name: "char-downcase", proc: _charDowncase, arity: fixed(1)}
+	builtinIsCharAlphabetic = &BuiltinProc{name: "char-alphabetic?", proc: _isCharAlphabetic, arity: fixed(1)}
+	builtinIsCharNumeric    = &BuiltinProc{name: "char-numeric?", proc: _isCharNumeric, arity: fixed(1)}
+	builtinIsCharWhitespace = &BuiltinProc{name: "char-whitespace?", proc: _isCharWhitespace, arity: fixed(1)}
+)
+
+func toChars(args []Value) ([]Char, error) {
+	var chars []Char
+	for _, arg := range args {
+		char, ok := arg.(Char)
+		if !ok {
+			return nil, errTypeMismatch
+		}
+		chars = append(chars, char)
+	}
+	return chars, nil
+}
+
+// charProc makes a procedure of a single character.
+func charProc(f func(Char) Value) func(...Value) (Value, error) {
+	return func(args ...Value) (Value, error) {
+		if len(args) != 1 {
+			return nil, errArityMismatch
+		}
+		chars, err := toChars(args)
+		if err != nil {
+			return nil, err
+		}
+		return f(chars[0]), nil
+	}
+}
+
+func _isChar(args ...Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, errArityMismatch
+	}
+	_, ok := args[0].(Char)
+	return Bool(ok), nil
+}
+
+var (
+	_charToInteger = charProc(func(c Char) Value { return Int(c) })
+	_charUpcase    = charProc(func(c Char) Value { return Char(unicode.ToUpper(rune(c))) })
+	_charDowncase  = charProc(func(c Char) Value { return Char(unicode.ToLower(rune(c))) })
+
+	_isCharAlphabetic = charProc(func(c Char) Value { return Bool(unicode.IsLetter(rune(c))) })
+	_isCharNumeric    = charProc(func(c Char) Value { return Bool(unicode.IsDigit(rune(c))) })
+	_isCharWhitespace = charProc(func(c Char) Value { return Bool(unicode.IsSpace(rune(c))) })
+)
+
+func _integerToChar(args ...Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, errArityMismatch
+	}
+	num, ok := args[0].(Int)
+	if !ok {
+		return nil, errTypeMismatch
+	}
+	if num < 0 || num > unicode.MaxRune || !utf8.ValidRune(rune(num)) {
+		return nil, errIndexOutOfRange
+	}
+	return Char(num), nil
+}
@@ -0,0 +1,44 @@
+package runtime
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_evalCharBuiltinProc(t *testing.T) {
+	testData := []struct {
+		op       *BuiltinProc
+		operands []Value
+		result   Value
+	}{
+		{builtinIsChar, []Value{Char('a')}, Bool(true)},
+		{builtinIsChar, []Value{String("a")}, Bool(false)},
+		{builtinCharToInteger, []Value{Char('A')}, Int(65)},
+		{builtinIntegerToChar, []Value{Int(955)}, Char('λ')},
+		{builtinCharUpcase, []Value{Char('a')}, Char('A')},
+		{builtinCharUpcase, []Value{Char('1')}, Char('1')},
+		{builtinCharDowncase, []Value{Char('Λ')}, Char('λ')},
+		{builtinIsCharAlphabetic, []Value{Char('x')}, Bool(true)},
+		{builtinIsCharAlphabetic, []Value{Char('1')}, Bool(false)},
+		{builtinIsCharNumeric, []Value{Char('1')}, Bool(true)},
+		{builtinIsCharNumeric, []Value{Char('x')}, Bool(false)},
+		{builtinIsCharWhitespace, []Value{Char('\n')}, Bool(true)},
+		{builtinIsCharWhitespace, []Value{Char('x')}, Bool(false)},
+	}
+
+	r := NewRuntime()
+	for _, test := range testData {
+		result, err := r.evalBuiltinProc(test.op, test.operands...)
+		if err != nil {
+			t.Errorf("\nerror: %s\ninput: {%s, %s}", err, test.op, test.operands)
+			break
+		}
+		if !reflect.DeepEqual(result, test.result) {
+			t.Errorf("\ninput: {%s, %s}\nexpect: {%s}\noutput: {%s}", test.op, test.operands, test.result, result)
+		}
+	}
+
+	if _, err := r.evalBuiltinProc(builtinIntegerToChar, Int(0xd800)); err == nil {
+		t.Error("expect an error for a surrogate code point")
+	}
+}
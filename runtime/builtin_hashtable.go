@@ -0,0 +1,185 @@
+package runtime
+
+import "errors"
+
+var (
+	builtinIsHashTable         = &BuiltinProc{name: "hash-table?", proc: _isHashTable, arity: fixed(1)}
+	builtinMakeHashTable       = &BuiltinProc{name: "make-hash-table", proc: _makeHashTable, arity: between(0, 1)}
+	builtinHashTableRef        = &BuiltinProc{name: "hash-table-ref", call: _hashTableRef, arity: between(2, 3)}
+	builtinHashTableRefDefault = &BuiltinProc{name: "hash-table-ref/default", proc: _hashTableRefDefault, arity: fixed(3)}
+	builtinHashTableSet        = &BuiltinProc{name: "hash-table-set!", proc: _hashTableSet, arity: fixed(3)}
+	builtinHashTableDelete     = &BuiltinProc{name: "hash-table-delete!", proc: _hashTableDelete, arity: fixed(2)}
+	builtinHashTableContains   = &BuiltinProc{name: "hash-table-contains?", proc: _hashTableContains, arity: fixed(2)}
+	builtinHashTableCount      = &BuiltinProc{name: "hash-table-count", proc: _hashTableCount, arity: fixed(1)}
+	builtinHashTableKeys       = &BuiltinProc{name: "hash-table-keys", proc: _hashTableKeys, arity: fixed(1)}
+	builtinHashTableValues     = &BuiltinProc{name: "hash-table-values", proc: _hashTableValues, arity: fixed(1)}
+	builtinHashTableToAlist    = &BuiltinProc{name: "hash-table->alist", proc: _hashTableToAlist, arity: fixed(1)}
+	builtinHashTableUpdate     = &BuiltinProc{name: "hash-table-update!", call: _hashTableUpdate, arity: between(3, 4)}
+)
+
+var (
+	errNoSuchKey = errors.New("no such key")
+)
+
+// toHashTable converts the first argument of a hash table procedure, which
+// takes at least min and at most max arguments.
+func toHashTable(args []Value, min, max int) (*HashTable, error) {
+	if len(args) < min || len(args) > max {
+		return nil, errArityMismatch
+	}
+	table, ok := args[0].(*HashTable)
+	if !ok {
+		return nil, errTypeMismatch
+	}
+	return table, nil
+}
+
+func _isHashTable(args ...Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, errArityMismatch
+	}
+	_, ok := args[0].(*HashTable)
+	return Bool(ok), nil
+}
+
+// _makeHashTable makes a hash table comparing the keys with the given eq?,
+// eqv? or equal?, which is equal? by default. Tables given eq? compare the
+// keys with eqv? as well.
+func _makeHashTable(args ...Value) (Value, error) {
+	if len(args) > 1 {
+		return nil, errArityMismatch
+	}
+	if len(args) == 0 {
+		return newHashTable(false), nil
+	}
+	switch args[0] {
+	case builtinEq, builtinEqv:
+		return newHashTable(true), nil
+	case builtinEqual:
+		return newHashTable(false), nil
+	}
+	return nil, errTypeMismatch
+}
+
+// _hashTableRef looks up a key, and calls the optional failure procedure if
+// the key is missing.
+func _hashTableRef(r *Runtime, args ...Value) (Value, error) {
+	table, err := toHashTable(args, 2, 3)
+	if err != nil {
+		return nil, err
+	}
+	if value, ok := table.get(args[1]); ok {
+		return value, nil
+	}
+	if len(args) == 3 {
+		return r.apply(args[2])
+	}
+	return nil, errNoSuchKey
+}
+
+func _hashTableRefDefault(args ...Value) (Value, error) {
+	table, err := toHashTable(args, 3, 3)
+	if err != nil {
+		return nil, err
+	}
+	if value, ok := table.get(args[1]); ok {
+		return value, nil
+	}
+	return args[2], nil
+}
+
+func _hashTableSet(args ...Value) (Value, error) {
+	table, err := toHashTable(args, 3, 3)
+	if err != nil {
+		return nil, err
+	}
+	table.set(args[1], args[2])
+	return Nil{}, nil
+}
+
+func _hashTableDelete(args ...Value) (Value, error) {
+	table, err := toHashTable(args, 2, 2)
+	if err != nil {
+		return nil, err
+	}
+	table.delete(args[1])
+	return Nil{}, nil
+}
+
+func _hashTableContains(args ...Value) (Value, error) {
+	table, err := toHashTable(args, 2, 2)
+	if err != nil {
+		return nil, err
+	}
+	_, ok := table.get(args[1])
+	return Bool(ok), nil
+}
+
+func _hashTableCount(args ...Value) (Value, error) {
+	table, err := toHashTable(args, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+	return Int(table.count), nil
+}
+
+func _hashTableKeys(args ...Value) (Value, error) {
+	table, err := toHashTable(args, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+	var keys []Value
+	table.each(func(key, value Value) {
+		keys = append(keys, key)
+	})
+	return _list(keys...)
+}
+
+func _hashTableValues(args ...Value) (Value, error) {
+	table, err := toHashTable(args, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+	var values []Value
+	table.each(func(key, value Value) {
+		values = append(values, value)
+	})
+	return _list(values...)
+}
+
+// _hashTableToAlist returns the entries as a list of (key . value) pairs.
+func _hashTableToAlist(args ...Value) (Value, error) {
+	table, err := toHashTable(args, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+	var pairs []Value
+	table.each(func(key, value Value) {
+		pairs = append(pairs, &Pair{first: key, second: value})
+	})
+	return _list(pairs...)
+}
+
+// _hashTableUpdate replaces the value of a key with the result of a procedure
+// on it. The optional failure procedure gives the value of a missing key.
+func _hashTableUpdate(r *Runtime, args ...Value) (Value, error) {
+	table, err := toHashTable(args, 3, 4)
+	if err != nil {
+		return nil, err
+	}
+	key := args[1]
+	value, ok := table.get(key)
+	if !ok {
+		if len(args) != 4 {
+			return nil, errNoSuchKey
+		}
+		if value, err = r.apply(args[3]); err != nil {
+			return nil, err
+		}
+	}
+	if value, err = r.apply(args[2], value); err != nil {
+		return nil, err
+	}
+	table.set(key, value)
+	return Nil{}, nil
+}
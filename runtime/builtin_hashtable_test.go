@@ -0,0 +1,107 @@
+package runtime
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func Test_evalHashTableBuiltinProc(t *testing.T) {
+	list := func(items ...Value) Value {
+		result, _ := _list(items...)
+		return result
+	}
+	r := NewRuntime()
+	equalTable := newHashTable(false)
+	eqTable := newHashTable(true)
+	key := list(Int(1), String("a"))
+
+	testData := []struct {
+		op       *BuiltinProc
+		operands []Value
+		result   Value
+	}{
+		{builtinIsHashTable, []Value{equalTable}, Bool(true)},
+		{builtinIsHashTable, []Value{list()}, Bool(false)},
+		{builtinHashTableSet, []Value{equalTable, key, Int(1)}, Nil{}},
+		{builtinHashTableSet, []Value{equalTable, Symbol{symbolMap("b")}, Int(2)}, Nil{}},
+		{builtinHashTableSet, []Value{equalTable, Float(1), Int(3)}, Nil{}},
+		{builtinHashTableRef, []Value{equalTable, list(Int(1), String("a"))}, Int(1)},
+		{builtinHashTableRef, []Value{equalTable, Int(1), builtinList}, Nil{}},
+		{builtinHashTableRefDefault, []Value{equalTable, Int(1), Bool(false)}, Bool(false)},
+		{builtinHashTableRefDefault, []Value{equalTable, Float(1), Bool(false)}, Int(3)},
+		{builtinHashTableContains, []Value{equalTable, Symbol{symbolMap("b")}}, Bool(true)},
+		{builtinHashTableCount, []Value{equalTable}, Int(3)},
+		{builtinHashTableSet, []Value{equalTable, Symbol{symbolMap("b")}, Int(4)}, Nil{}},
+		{builtinHashTableDelete, []Value{equalTable, Float(1)}, Nil{}},
+		{builtinHashTableDelete, []Value{equalTable, Float(2)}, Nil{}},
+		{builtinHashTableKeys, []Value{equalTable}, list(key, Symbol{symbolMap("b")})},
+		{builtinHashTableValues, []Value{equalTable}, list(Int(1), Int(4))},
+		{builtinHashTableToAlist, []Value{equalTable}, list(
+			&Pair{first: key, second: Int(1)},
+			&Pair{first: Symbol{symbolMap("b")}, second: Int(4)},
+		)},
+		{builtinHashTableUpdate, []Value{equalTable, Symbol{symbolMap("b")}, builtinSub}, Nil{}},
+		{builtinHashTableRef, []Value{equalTable, Symbol{symbolMap("b")}}, Int(-4)},
+		{builtinHashTableUpdate, []Value{equalTable, String("c"), builtinList, builtinMul}, Nil{}},
+		{builtinHashTableRef, []Value{equalTable, String("c")}, list(Int(1))},
+
+		{builtinHashTableSet, []Value{eqTable, key, Int(1)}, Nil{}},
+		{builtinHashTableSet, []Value{eqTable, Symbol{symbolMap("b")}, Int(2)}, Nil{}},
+		{builtinHashTableRef, []Value{eqTable, key}, Int(1)},
+		{builtinHashTableRef, []Value{eqTable, Symbol{symbolMap("b")}}, Int(2)},
+		{builtinHashTableContains, []Value{eqTable, list(Int(1), String("a"))}, Bool(false)},
+		{builtinHashTableSet, []Value{eqTable, bigInt("100000000000000000000"), Int(3)}, Nil{}},
+		{builtinHashTableSet, []Value{eqTable, rat(1, 2), Int(4)}, Nil{}},
+		{builtinHashTableRef, []Value{eqTable, bigInt("100000000000000000000")}, Int(3)},
+		{builtinHashTableRef, []Value{eqTable, rat(1, 2)}, Int(4)},
+		{builtinHashTableContains, []Value{eqTable, Float(0.5)}, Bool(false)},
+	}
+
+	for _, test := range testData {
+		result, err := r.evalBuiltinProc(test.op, test.operands...)
+		if err != nil {
+			t.Errorf("\nerror: %s\ninput: {%s, %s}", err, test.op, test.operands)
+			break
+		}
+		if !reflect.DeepEqual(result, test.result) {
+			t.Errorf("\ninput: {%s, %s}\nexpect: {%s}\noutput: {%s}", test.op, test.operands, test.result, result)
+		}
+	}
+
+	errData := []struct {
+		op       *BuiltinProc
+		operands []Value
+		err      error
+	}{
+		{builtinMakeHashTable, []Value{builtinEqNum}, errTypeMismatch},
+		{builtinHashTableRef, []Value{equalTable, Int(5)}, errNoSuchKey},
+		{builtinHashTableUpdate, []Value{equalTable, Int(5), builtinSub}, errNoSuchKey},
+		{builtinHashTableSet, []Value{list(), Int(1), Int(1)}, errTypeMismatch},
+		{builtinHashTableCount, nil, errArityMismatch},
+	}
+	for _, test := range errData {
+		if _, err := r.evalBuiltinProc(test.op, test.operands...); err != test.err {
+			t.Errorf("\ninput: {%s, %s}\nexpect: %v\noutput: %v", test.op, test.operands, test.err, err)
+		}
+	}
+}
+
+func Test_hashTableRehash(t *testing.T) {
+	table := newHashTable(false)
+	for i := 0; i < 100; i++ {
+		table.set(String(fmt.Sprint(i)), Int(i))
+	}
+	for i := 0; i < 100; i += 3 {
+		table.delete(String(fmt.Sprint(i)))
+	}
+	if table.count != 66 || len(table.entries) > 2*table.count {
+		t.Errorf("count: %d, entries: %d", table.count, len(table.entries))
+	}
+	for i := 0; i < 100; i++ {
+		value, ok := table.get(String(fmt.Sprint(i)))
+		if ok == (i%3 == 0) || ok && value != Int(i) {
+			t.Errorf("key %d: %v %v", i, value, ok)
+		}
+	}
+}
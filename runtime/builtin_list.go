@@ -0,0 +1,407 @@
+package runtime
+
+import (
+	"errors"
+	"sort"
+)
+
+var (
+	builtinApply     = &BuiltinProc{name: "apply", call: _apply, arity: atLeast(2)}
+	builtinMap       = &BuiltinProc{name: "map", call: _map, arity: atLeast(2)}
+	builtinForEach   = &BuiltinProc{name: "for-each", call: _forEach, arity: atLeast(2)}
+	builtinFilter    = &BuiltinProc{name: "filter", call: _filter, arity: fixed(2)}
+	builtinReduce    = &BuiltinProc{name: "reduce", call: _reduce, arity: fixed(3)}
+	builtinFoldLeft  = &BuiltinProc{name: "fold-left", call: _foldLeft, arity: atLeast(3)}
+	builtinFoldRight = &BuiltinProc{name: "fold-right", call: _foldRight, arity: atLeast(3)}
+	builtinSort      = &BuiltinProc{name: "sort", call: _sort, arity: fixed(2)}
+	builtinAppend    = &BuiltinProc{name: "append", proc: _append, arity: atLeast(0)}
+	builtinReverse   = &BuiltinProc{name: "reverse", proc: _reverse, arity: fixed(1)}
+	builtinLength    = &BuiltinProc{name: "length", proc: _length, arity: fixed(1)}
+	builtinListRef   = &BuiltinProc{name: "list-ref", proc: _listRef, arity: fixed(2)}
+	builtinListTail  = &BuiltinProc{name: "list-tail", proc: _listTail, arity: fixed(2)}
+	builtinLastPair  = &BuiltinProc{name: "last-pair", proc: _lastPair, arity: fixed(1)}
+	builtinListCopy  = &BuiltinProc{name: "list-copy", proc: _listCopy, arity: fixed(1)}
+	builtinIota      = &BuiltinProc{name: "iota", proc: _iota, arity: between(1, 3)}
+)
+
+// maxLength bounds the length of the lists and vectors made from a count, so
+// that a huge count is an error rather than a failed allocation.
+const maxLength = 1 << 24
+
+var (
+	errTooLong = errors.New("too long")
+)
+
+// toLength checks that arg is a count of items no greater than maxLength.
+func toLength(arg Value) (int, error) {
+	if big, ok := arg.(BigInt); ok {
+		if big.Sign() < 0 {
+			return 0, errIndexOutOfRange
+		}
+		return 0, errTooLong
+	}
+	k, ok := arg.(Int)
+	if !ok {
+		return 0, errTypeMismatch
+	}
+	if k < 0 {
+		return 0, errIndexOutOfRange
+	}
+	if k > maxLength {
+		return 0, errTooLong
+	}
+	return int(k), nil
+}
+
+// zipLists collects the items of the lists at each index, up to the length
+// of the shortest list.
+func zipLists(args []Value) ([][]Value, error) {
+	lists := make([][]Value, len(args))
+	size := -1
+	for i, arg := range args {
+		var err error
+		if lists[i], err = listToSlice(arg); err != nil {
+			return nil, err
+		}
+		if size < 0 || len(lists[i]) < size {
+			size = len(lists[i])
+		}
+	}
+	rows := make([][]Value, size)
+	for i := range rows {
+		rows[i] = make([]Value, len(lists))
+		for j, list := range lists {
+			rows[i][j] = list[i]
+		}
+	}
+	return rows, nil
+}
+
+// _apply calls a procedure with the arguments between, followed by the items
+// of the last argument.
+func _apply(r *Runtime, args ...Value) (Value, error) {
+	if len(args) < 2 {
+		return nil, errArityMismatch
+	}
+	rest, err := listToSlice(args[len(args)-1])
+	if err != nil {
+		return nil, err
+	}
+	operands := append(append([]Value{}, args[1:len(args)-1]...), rest...)
+	return r.apply(args[0], operands...)
+}
+
+func _map(r *Runtime, args ...Value) (Value, error) {
+	if len(args) < 2 {
+		return nil, errArityMismatch
+	}
+	rows, err := zipLists(args[1:])
+	if err != nil {
+		return nil, err
+	}
+	results := make([]Value, len(rows))
+	for i, row := range rows {
+		if results[i], err = r.apply(args[0], row...); err != nil {
+			return nil, err
+		}
+	}
+	return _list(results...)
+}
+
+func _forEach(r *Runtime, args ...Value) (Value, error) {
+	if len(args) < 2 {
+		return nil, errArityMismatch
+	}
+	rows, err := zipLists(args[1:])
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if _, err := r.apply(args[0], row...); err != nil {
+			return nil, err
+		}
+	}
+	return Nil{}, nil
+}
+
+// _filter returns a list of the items which satisfy a predicate.
+func _filter(r *Runtime, args ...Value) (Value, error) {
+	if len(args) != 2 {
+		return nil, errArityMismatch
+	}
+	items, err := listToSlice(args[1])
+	if err != nil {
+		return nil, err
+	}
+	var results []Value
+	for _, item := range items {
+		ok, err := r.apply(args[0], item)
+		if err != nil {
+			return nil, err
+		}
+		if isTrue(ok) {
+			results = append(results, item)
+		}
+	}
+	return _list(results...)
+}
+
+// _reduce combines the items of a list from left to right as (f item acc),
+// starting with the first item. It returns the identity for an empty list.
+func _reduce(r *Runtime, args ...Value) (Value, error) {
+	if len(args) != 3 {
+		return nil, errArityMismatch
+	}
+	items, err := listToSlice(args[2])
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return args[1], nil
+	}
+	acc := items[0]
+	for _, item := range items[1:] {
+		if acc, err = r.apply(args[0], item, acc); err != nil {
+			return nil, err
+		}
+	}
+	return acc, nil
+}
+
+// _foldLeft combines the items of the lists at each index from left to right
+// as (f acc item1 item2 ...).
+func _foldLeft(r *Runtime, args ...Value) (Value, error) {
+	if len(args) < 3 {
+		return nil, errArityMismatch
+	}
+	rows, err := zipLists(args[2:])
+	if err != nil {
+		return nil, err
+	}
+	acc := args[1]
+	for _, row := range rows {
+		if acc, err = r.apply(args[0], append([]Value{acc}, row...)...); err != nil {
+			return nil, err
+		}
+	}
+	return acc, nil
+}
+
+// _foldRight combines the items of the lists at each index from right to
+// left as (f item1 item2 ... acc).
+func _foldRight(r *Runtime, args ...Value) (Value, error) {
+	if len(args) < 3 {
+		return nil, errArityMismatch
+	}
+	rows, err := zipLists(args[2:])
+	if err != nil {
+		return nil, err
+	}
+	acc := args[1]
+	for i := len(rows) - 1; i >= 0; i-- {
+		if acc, err = r.apply(args[0], append(rows[i], acc)...); err != nil {
+			return nil, err
+		}
+	}
+	return acc, nil
+}
+
+// _sort returns a new list or vector of the items in the order given by a
+// less-than procedure. Equal items keep their original order.
+func _sort(r *Runtime, args ...Value) (Value, error) {
+	if len(args) != 2 {
+		return nil, errArityMismatch
+	}
+	var items []Value
+	vec, isVector := args[0].(*Vector)
+	if isVector {
+		items = append([]Value{}, vec.items...)
+	} else {
+		var err error
+		if items, err = listToSlice(args[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	sort.SliceStable(items, func(i, j int) bool {
+		if err != nil {
+			return false
+		}
+		var less Value
+		less, err = r.apply(args[1], items[i], items[j])
+		return err == nil && isTrue(less)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if isVector {
+		return &Vector{items: items}, nil
+	}
+	return _list(items...)
+}
+
+// _append returns a list of the items of all the lists. The last argument is
+// shared rather than copied, and may be any value.
+func _append(args ...Value) (Value, error) {
+	if len(args) == 0 {
+		return Nil{}, nil
+	}
+	var items []Value
+	for _, arg := range args[:len(args)-1] {
+		list, err := listToSlice(arg)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, list...)
+	}
+	result := args[len(args)-1]
+	for i := len(items) - 1; i >= 0; i-- {
+		result = &Pair{first: items[i], second: result}
+	}
+	return result, nil
+}
+
+func _reverse(args ...Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, errArityMismatch
+	}
+	items, err := listToSlice(args[0])
+	if err != nil {
+		return nil, err
+	}
+	var result Value = Nil{}
+	for _, item := range items {
+		result = &Pair{first: item, second: result}
+	}
+	return result, nil
+}
+
+func _length(args ...Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, errArityMismatch
+	}
+	if !isList(args[0]) {
+		return nil, errTypeMismatch
+	}
+	n := 0
+	for list := args[0]; list != (Nil{}); list = list.(*Pair).second {
+		n++
+	}
+	return Int(n), nil
+}
+
+// listTail skips the first k pairs of a list.
+func listTail(list, k Value) (Value, error) {
+	if _, ok := k.(BigInt); ok {
+		return nil, errIndexOutOfRange
+	}
+	n, ok := k.(Int)
+	if !ok {
+		return nil, errTypeMismatch
+	}
+	if n < 0 {
+		return nil, errIndexOutOfRange
+	}
+	for ; n > 0; n-- {
+		p, ok := list.(*Pair)
+		if !ok {
+			return nil, errIndexOutOfRange
+		}
+		list = p.second
+	}
+	return list, nil
+}
+
+func _listRef(args ...Value) (Value, error) {
+	if len(args) != 2 {
+		return nil, errArityMismatch
+	}
+	tail, err := listTail(args[0], args[1])
+	if err != nil {
+		return nil, err
+	}
+	p, ok := tail.(*Pair)
+	if !ok {
+		return nil, errIndexOutOfRange
+	}
+	return p.first, nil
+}
+
+func _listTail(args ...Value) (Value, error) {
+	if len(args) != 2 {
+		return nil, errArityMismatch
+	}
+	return listTail(args[0], args[1])
+}
+
+func _lastPair(args ...Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, errArityMismatch
+	}
+	p, ok := args[0].(*Pair)
+	if !ok {
+		return nil, errTypeMismatch
+	}
+	for {
+		next, ok := p.second.(*Pair)
+		if !ok {
+			return p, nil
+		}
+		p = next
+	}
+}
+
+// _listCopy copies the pairs of a list, keeping the tail of an improper one.
+func _listCopy(args ...Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, errArityMismatch
+	}
+	var head Value = Nil{}
+	last := &head
+	list := args[0]
+	for {
+		p, ok := list.(*Pair)
+		if !ok {
+			*last = list
+			return head, nil
+		}
+		copied := &Pair{first: p.first}
+		*last = copied
+		last = &copied.second
+		list = p.second
+	}
+}
+
+// _iota returns a list of count numbers, from start (0 by default) by step (1
+// by default).
+func _iota(args ...Value) (Value, error) {
+	if len(args) < 1 || len(args) > 3 {
+		return nil, errArityMismatch
+	}
+	count, err := toLength(args[0])
+	if err != nil {
+		return nil, err
+	}
+	start, step := Value(Int(0)), Value(Int(1))
+	if len(args) > 1 {
+		start = args[1]
+	}
+	if len(args) > 2 {
+		step = args[2]
+	}
+	if err := checkNumbers([]Value{start, step}); err != nil {
+		return nil, err
+	}
+	items := make([]Value, count)
+	for i := range items {
+		offset, err := arithMul.apply(Int(i), step)
+		if err != nil {
+			return nil, err
+		}
+		if items[i], err = arithAdd.apply(start, offset); err != nil {
+			return nil, err
+		}
+	}
+	return _list(items...)
+}
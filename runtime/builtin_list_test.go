@@ -0,0 +1,113 @@
+package runtime
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_evalListBuiltinProc(t *testing.T) {
+	list := func(items ...Value) Value {
+		result, _ := _list(items...)
+		return result
+	}
+	pair := func(a, b Value) *Pair {
+		return &Pair{first: a, second: b}
+	}
+
+	testData := []struct {
+		op       *BuiltinProc
+		operands []Value
+		result   Value
+	}{
+		{builtinApply, []Value{builtinAdd, Int(1), list(Int(2), Int(3))}, Int(6)},
+		{builtinApply, []Value{builtinList, Nil{}}, Nil{}},
+		{builtinMap, []Value{builtinAdd, list(Int(1), Int(2), Int(3)), list(Int(10), Int(20))}, list(Int(11), Int(22))},
+		{builtinMap, []Value{builtinCar, Nil{}}, Nil{}},
+		{builtinForEach, []Value{builtinCar, list(pair(Int(1), Int(2)))}, Nil{}},
+		{builtinFilter, []Value{builtinIsPair, list(Int(1), list(Int(2)), Nil{})}, list(list(Int(2)))},
+		{builtinReduce, []Value{builtinSub, Int(0), list(Int(1), Int(2), Int(3))}, Int(2)},
+		{builtinReduce, []Value{builtinAdd, Int(0), Nil{}}, Int(0)},
+		{builtinFoldLeft, []Value{builtinCons, Nil{}, list(Int(1), Int(2))}, pair(pair(Nil{}, Int(1)), Int(2))},
+		{builtinFoldLeft, []Value{builtinAdd, Int(0), list(Int(1), Int(2)), list(Int(3), Int(4), Int(5))}, Int(10)},
+		{builtinFoldRight, []Value{builtinCons, Nil{}, list(Int(1), Int(2))}, list(Int(1), Int(2))},
+		{builtinFoldRight, []Value{builtinList, Int(0), list(Int(1), Int(2)), list(Int(3), Int(4))}, list(Int(1), Int(3), list(Int(2), Int(4), Int(0)))},
+		{builtinSort, []Value{list(Int(3), Int(1), Int(2)), builtinLt}, list(Int(1), Int(2), Int(3))},
+		{builtinSort, []Value{&Vector{items: []Value{Int(3), Int(1), Int(2)}}, builtinGt}, &Vector{items: []Value{Int(3), Int(2), Int(1)}}},
+		{builtinSort, []Value{Nil{}, builtinLt}, Nil{}},
+		{builtinAppend, nil, Nil{}},
+		{builtinAppend, []Value{Int(1)}, Int(1)},
+		{builtinAppend, []Value{list(Int(1)), Nil{}, list(Int(2)), list(Int(3))}, list(Int(1), Int(2), Int(3))},
+		{builtinAppend, []Value{list(Int(1)), Int(2)}, pair(Int(1), Int(2))},
+		{builtinReverse, []Value{list(Int(1), Int(2), Int(3))}, list(Int(3), Int(2), Int(1))},
+		{builtinLength, []Value{Nil{}}, Int(0)},
+		{builtinLength, []Value{list(Int(1), Int(2), Int(3))}, Int(3)},
+		{builtinListRef, []Value{list(Int(1), Int(2), Int(3)), Int(2)}, Int(3)},
+		{builtinListTail, []Value{list(Int(1), Int(2), Int(3)), Int(1)}, list(Int(2), Int(3))},
+		{builtinListTail, []Value{Nil{}, Int(0)}, Nil{}},
+		{builtinLastPair, []Value{list(Int(1), Int(2), Int(3))}, list(Int(3))},
+		{builtinLastPair, []Value{pair(Int(1), Int(2))}, pair(Int(1), Int(2))},
+		{builtinListCopy, []Value{pair(Int(1), pair(Int(2), Int(3)))}, pair(Int(1), pair(Int(2), Int(3)))},
+		{builtinListCopy, []Value{Int(1)}, Int(1)},
+		{builtinIota, []Value{Int(3)}, list(Int(0), Int(1), Int(2))},
+		{builtinIota, []Value{Int(3), Int(1), rat(1, 2)}, list(Int(1), rat(3, 2), Int(2))},
+		{builtinIota, []Value{Int(2), Float(0), Int(1)}, list(Float(0), Float(1))},
+		{builtinIota, []Value{Int(0)}, Nil{}},
+		{builtinMember, []Value{Float(2), list(Int(1), Int(2)), builtinEqNum}, list(Int(2))},
+		{builtinMember, []Value{Float(2), list(Int(1), Int(2))}, Bool(false)},
+		{builtinAssoc, []Value{Int(1), list(pair(Float(1), Int(2))), builtinEqNum}, pair(Float(1), Int(2))},
+	}
+
+	r := NewRuntime()
+	for _, test := range testData {
+		result, err := r.evalBuiltinProc(test.op, test.operands...)
+		if err != nil {
+			t.Errorf("\nerror: %s\ninput: {%s, %s}", err, test.op, test.operands)
+			break
+		}
+		if !reflect.DeepEqual(result, test.result) {
+			t.Errorf("\ninput: {%s, %s}\nexpect: {%s}\noutput: {%s}", test.op, test.operands, test.result, result)
+		}
+	}
+
+	cycle := pair(Int(1), nil)
+	cycle.second = cycle
+	errData := []struct {
+		op       *BuiltinProc
+		operands []Value
+		err      error
+	}{
+		{builtinApply, []Value{builtinAdd, Int(1)}, errTypeMismatch},
+		{builtinApply, []Value{Int(1), Nil{}}, errNotProcedure},
+		{builtinMap, []Value{builtinAdd, pair(Int(1), Int(2))}, errTypeMismatch},
+		{builtinMap, []Value{builtinCar, list(Int(1))}, errTypeMismatch},
+		{builtinFilter, []Value{Int(1), list(Int(1))}, errNotProcedure},
+		{builtinSort, []Value{list(Int(2), String("a")), builtinLt}, errTypeMismatch},
+		{builtinAppend, []Value{Int(1), Nil{}}, errTypeMismatch},
+		{builtinLength, []Value{cycle}, errTypeMismatch},
+		{builtinLength, []Value{pair(Int(1), Int(2))}, errTypeMismatch},
+		{builtinListRef, []Value{list(Int(1)), Int(1)}, errIndexOutOfRange},
+		{builtinListTail, []Value{list(Int(1)), Int(-1)}, errIndexOutOfRange},
+		{builtinLastPair, []Value{Nil{}}, errTypeMismatch},
+		{builtinIota, []Value{Int(-1)}, errIndexOutOfRange},
+		{builtinIota, []Value{Int(1), String("a")}, errTypeMismatch},
+		{builtinIota, []Value{Int(99999999999999)}, errTooLong},
+		{builtinIota, []Value{bigInt("99999999999999999999")}, errTooLong},
+	}
+	for _, test := range errData {
+		if _, err := r.evalBuiltinProc(test.op, test.operands...); err != test.err {
+			t.Errorf("\ninput: {%s, %s}\nexpect: %v\noutput: %v", test.op, test.operands, test.err, err)
+		}
+	}
+}
+
+// Test_appendShare checks that append shares its last argument.
+func Test_appendShare(t *testing.T) {
+	last := &Pair{first: Int(2), second: Nil{}}
+	result, err := _append(&Pair{first: Int(1), second: Nil{}}, last)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.(*Pair).second != last {
+		t.Error("expect the last list to be shared")
+	}
+}
@@ -0,0 +1,223 @@
+package runtime
+
+import (
+	"math"
+	"math/big"
+)
+
+var (
+	builtinExactToInexact = &BuiltinProc{name: "exact->inexact", proc: _exactToInexact, arity: fixed(1)}
+	builtinInexactToExact = &BuiltinProc{name: "inexact->exact", proc: _inexactToExact, arity: fixed(1)}
+	builtinFloor          = &BuiltinProc{name: "floor", proc: _floor, arity: fixed(1)}
+	builtinCeiling        = &BuiltinProc{name: "ceiling", proc: _ceiling, arity: fixed(1)}
+	builtinRound          = &BuiltinProc{name: "round", proc: _round, arity: fixed(1)}
+	builtinTruncate       = &BuiltinProc{name: "truncate", proc: _truncate, arity: fixed(1)}
+	builtinSqrt           = &BuiltinProc{name: "sqrt", proc: _sqrt, arity: fixed(1)}
+	builtinExpt           = &BuiltinProc{name: "expt", proc: _expt, arity: fixed(2)}
+	builtinExp            = &BuiltinProc{name: "exp", proc: _exp, arity: fixed(1)}
+	builtinLog            = &BuiltinProc{name: "log", proc: _log, arity: between(1, 2)}
+	builtinSin            = &BuiltinProc{name: "sin", proc: _sin, arity: fixed(1)}
+	builtinCos            = &BuiltinProc{name: "cos", proc: _cos, arity: fixed(1)}
+	builtinAtan           = &BuiltinProc{name: "atan", proc: _atan, arity: between(1, 2)}
+	builtinNumerator      = &BuiltinProc{name: "numerator", proc: _numerator, arity: fixed(1)}
+	builtinDenominator    = &BuiltinProc{name: "denominator", proc: _denominator, arity: fixed(1)}
+	builtinIsExact        = &BuiltinProc{name: "exact?", proc: _isExact, arity: fixed(1)}
+	builtinIsInexact      = &BuiltinProc{name: "inexact?", proc: _isInexact, arity: fixed(1)}
+)
+
+// numberProc makes a procedure of a single number.
+func numberProc(f func(Value) (Value, error)) func(...Value) (Value, error) {
+	return func(args ...Value) (Value, error) {
+		if len(args) != 1 {
+			return nil, errArityMismatch
+		}
+		if err := checkNumbers(args); err != nil {
+			return nil, err
+		}
+		return f(args[0])
+	}
+}
+
+// roundProc makes a rounding procedure, which keeps integers unchanged,
+// rounds rationals to integers and floats to floats.
+func roundProc(f func(float64) float64, r func(*big.Rat) *big.Int) func(...Value) (Value, error) {
+	return numberProc(func(num Value) (Value, error) {
+		switch x := num.(type) {
+		case Rat:
+			return normalize(r(x.Rat)), nil
+		case Float:
+			return Float(f(float64(x))), nil
+		}
+		return num, nil
+	})
+}
+
+func floorRat(x *big.Rat) *big.Int {
+	// the denominator is always positive, so that Euclidean division floors
+	return new(big.Int).Div(x.Num(), x.Denom())
+}
+
+func ceilRat(x *big.Rat) *big.Int {
+	return new(big.Int).Neg(floorRat(new(big.Rat).Neg(x)))
+}
+
+func truncRat(x *big.Rat) *big.Int {
+	return new(big.Int).Quo(x.Num(), x.Denom())
+}
+
+// roundRat rounds to the nearest integer, and to the even one on a tie.
+func roundRat(x *big.Rat) *big.Int {
+	half := new(big.Rat).Add(x, big.NewRat(1, 2))
+	n := floorRat(half)
+	if half.IsInt() && n.Bit(0) == 1 {
+		n.Sub(n, big.NewInt(1))
+	}
+	return n
+}
+
+// floatProc makes a procedure computing an inexact result.
+func floatProc(f func(float64) float64) func(...Value) (Value, error) {
+	return numberProc(func(num Value) (Value, error) {
+		return Float(f(float64(toFloat(num)))), nil
+	})
+}
+
+var (
+	_exactToInexact = numberProc(func(num Value) (Value, error) {
+		return toFloat(num), nil
+	})
+	_inexactToExact = numberProc(func(num Value) (Value, error) {
+		if x, ok := num.(Float); ok {
+			return toExact(x)
+		}
+		return num, nil
+	})
+
+	_floor    = roundProc(math.Floor, floorRat)
+	_ceiling  = roundProc(math.Ceil, ceilRat)
+	_round    = roundProc(math.RoundToEven, roundRat)
+	_truncate = roundProc(math.Trunc, truncRat)
+
+	_exp = floatProc(math.Exp)
+	_sin = floatProc(math.Sin)
+	_cos = floatProc(math.Cos)
+)
+
+// _sqrt is exact for the squares of exact numbers.
+var _sqrt = numberProc(func(num Value) (Value, error) {
+	if isExact(num) && toRat(num).Sign() >= 0 {
+		x := toRat(num)
+		numer, ok1 := sqrtInt(x.Num())
+		denom, ok2 := sqrtInt(x.Denom())
+		if ok1 && ok2 {
+			return normalizeRat(new(big.Rat).SetFrac(numer, denom)), nil
+		}
+	}
+	return Float(math.Sqrt(float64(toFloat(num)))), nil
+})
+
+// sqrtInt returns the square root of n if n is a perfect square.
+func sqrtInt(n *big.Int) (*big.Int, bool) {
+	root := new(big.Int).Sqrt(n)
+	return root, new(big.Int).Mul(root, root).Cmp(n) == 0
+}
+
+// _expt is exact for an exact base raised to an integer power.
+func _expt(args ...Value) (Value, error) {
+	if len(args) != 2 {
+		return nil, errArityMismatch
+	}
+	if err := checkNumbers(args); err != nil {
+		return nil, err
+	}
+	base := args[0]
+	power, ok := args[1].(Int)
+	if !isExact(base) || !ok {
+		return Float(math.Pow(float64(toFloat(args[0])), float64(toFloat(args[1])))), nil
+	}
+
+	var result Value = Int(1)
+	var square Value = base
+	negative := power < 0
+	if negative {
+		power = -power
+	}
+	for ; power > 0; power >>= 1 {
+		var err error
+		if power&1 == 1 {
+			if result, err = arithMul.apply(result, square); err != nil {
+				return nil, err
+			}
+		}
+		if power > 1 {
+			if square, err = arithMul.apply(square, square); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if negative {
+		return arithDiv.apply(Int(1), result)
+	}
+	return result, nil
+}
+
+// _log computes the natural logarithm, or the logarithm in the base given
+// as the second argument.
+func _log(args ...Value) (Value, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, errArityMismatch
+	}
+	if err := checkNumbers(args); err != nil {
+		return nil, err
+	}
+	result := math.Log(float64(toFloat(args[0])))
+	if len(args) == 2 {
+		result /= math.Log(float64(toFloat(args[1])))
+	}
+	return Float(result), nil
+}
+
+// _atan computes the arctangent of y, or of y/x in the quadrant of (x, y)
+// when x is given as the second argument.
+func _atan(args ...Value) (Value, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, errArityMismatch
+	}
+	if err := checkNumbers(args); err != nil {
+		return nil, err
+	}
+	if len(args) == 2 {
+		return Float(math.Atan2(float64(toFloat(args[0])), float64(toFloat(args[1])))), nil
+	}
+	return Float(math.Atan(float64(toFloat(args[0])))), nil
+}
+
+// _numerator returns the numerator of a number in lowest terms. The result is
+// inexact for an inexact number.
+var _numerator = numberProc(func(num Value) (Value, error) {
+	return ratPart(num, (*big.Rat).Num)
+})
+
+// _denominator returns the denominator of a number in lowest terms, which is
+// always positive. The result is inexact for an inexact number.
+var _denominator = numberProc(func(num Value) (Value, error) {
+	return ratPart(num, (*big.Rat).Denom)
+})
+
+func ratPart(num Value, part func(*big.Rat) *big.Int) (Value, error) {
+	if x, ok := num.(Float); ok {
+		if !isFinite(x) {
+			return nil, errNoExact
+		}
+		return toFloat(normalize(part(floatToRat(x)))), nil
+	}
+	return normalize(new(big.Int).Set(part(toRat(num)))), nil
+}
+
+var _isExact = numberProc(func(num Value) (Value, error) {
+	return Bool(isExact(num)), nil
+})
+
+var _isInexact = numberProc(func(num Value) (Value, error) {
+	return Bool(!isExact(num)), nil
+})
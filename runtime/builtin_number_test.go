@@ -0,0 +1,192 @@
+package runtime
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_evalNumberBuiltinProc(t *testing.T) {
+	testData := []struct {
+		op       *BuiltinProc
+		operands []Value
+		result   Value
+	}{
+		{builtinAdd, []Value{Int(1), Float(2.5)}, Float(3.5)},
+		{builtinAdd, []Value{Float(0.5), Float(0.25), Int(1)}, Float(1.75)},
+		{builtinSub, []Value{Float(1.5)}, Float(-1.5)},
+		{builtinSub, []Value{Int(3), Float(0.5)}, Float(2.5)},
+		{builtinMul, []Value{Int(3), Float(0.5)}, Float(1.5)},
+		{builtinDiv, []Value{Int(7), Int(2)}, rat(7, 2)},
+		{builtinDiv, []Value{Int(8), Int(2), Int(2)}, Int(2)},
+		{builtinDiv, []Value{Float(1), Int(4)}, Float(0.25)},
+		{builtinDiv, []Value{Float(1), Int(0)}, Float(math.Inf(1))},
+		{builtinEqNum, []Value{Int(1), Float(1)}, Bool(true)},
+		{builtinEqNum, []Value{Float(math.NaN()), Float(math.NaN())}, Bool(false)},
+		{builtinLt, []Value{Int(1), Float(1.5), Int(2)}, Bool(true)},
+		{builtinLt, []Value{Float(1.5), Int(1)}, Bool(false)},
+		{builtinGte, []Value{Float(2), Int(2), Float(1.5)}, Bool(true)},
+		{builtinAdd, []Value{Int(math.MaxInt64), Int(1)}, bigInt("9223372036854775808")},
+		{builtinAdd, []Value{bigInt("9223372036854775808"), Int(-1)}, Int(math.MaxInt64)},
+		{builtinAdd, []Value{bigInt("9223372036854775808"), Float(0.5)}, Float(9223372036854775808.5)},
+		{builtinSub, []Value{Int(math.MinInt64), Int(1)}, bigInt("-9223372036854775809")},
+		{builtinSub, []Value{Int(math.MinInt64)}, bigInt("9223372036854775808")},
+		{builtinSub, []Value{bigInt("-9223372036854775809"), Int(-1)}, Int(math.MinInt64)},
+		{builtinMul, []Value{Int(1 << 32), Int(1 << 32)}, bigInt("18446744073709551616")},
+		{builtinMul, []Value{Int(-1), Int(math.MinInt64)}, bigInt("9223372036854775808")},
+		{builtinDiv, []Value{bigInt("18446744073709551616"), Int(1 << 32)}, Int(1 << 32)},
+		{builtinDiv, []Value{Int(math.MinInt64), Int(-1)}, bigInt("9223372036854775808")},
+		{builtinDiv, []Value{bigInt("18446744073709551616"), Int(3)}, Rat{new(big.Rat).SetFrac(bigInt("18446744073709551616").Int, big.NewInt(3))}},
+		{builtinMod, []Value{bigInt("18446744073709551617"), Int(1 << 32)}, Int(1)},
+		{builtinMod, []Value{Int(-7), Int(2)}, Int(-1)},
+		{builtinEqNum, []Value{bigInt("18446744073709551616"), bigInt("18446744073709551616")}, Bool(true)},
+		{builtinLt, []Value{Int(1), bigInt("18446744073709551616"), Float(1e20)}, Bool(true)},
+		{builtinGt, []Value{Int(1), bigInt("-18446744073709551616")}, Bool(true)},
+		{builtinSqrt, []Value{bigInt("340282366920938463463374607431768211456")}, bigInt("18446744073709551616")},
+		{builtinExpt, []Value{Int(2), Int(64)}, bigInt("18446744073709551616")},
+		{builtinEqual, []Value{bigInt("18446744073709551616"), bigInt("18446744073709551616")}, Bool(true)},
+		{builtinExactToInexact, []Value{Int(3)}, Float(3)},
+		{builtinInexactToExact, []Value{Float(-3)}, Int(-3)},
+		{builtinInexactToExact, []Value{Int(3)}, Int(3)},
+		{builtinInexactToExact, []Value{Float(1e20)}, bigInt("100000000000000000000")},
+		{builtinFloor, []Value{Float(-2.5)}, Float(-3)},
+		{builtinFloor, []Value{Int(5)}, Int(5)},
+		{builtinCeiling, []Value{Float(2.1)}, Float(3)},
+		{builtinRound, []Value{Float(2.5)}, Float(2)},
+		{builtinRound, []Value{Float(3.5)}, Float(4)},
+		{builtinTruncate, []Value{Float(-2.7)}, Float(-2)},
+		{builtinSqrt, []Value{Int(144)}, Int(12)},
+		{builtinSqrt, []Value{Int(2)}, Float(math.Sqrt2)},
+		{builtinSqrt, []Value{Float(6.25)}, Float(2.5)},
+		{builtinExpt, []Value{Int(3), Int(4)}, Int(81)},
+		{builtinExpt, []Value{Int(2), Int(0)}, Int(1)},
+		{builtinExpt, []Value{Int(2), Int(-2)}, rat(1, 4)},
+		{builtinExpt, []Value{Float(4), Float(0.5)}, Float(2)},
+		{builtinExp, []Value{Int(0)}, Float(1)},
+		{builtinLog, []Value{Int(1)}, Float(0)},
+		{builtinLog, []Value{Int(8), Int(2)}, Float(3)},
+		{builtinSin, []Value{Int(0)}, Float(0)},
+		{builtinCos, []Value{Int(0)}, Float(1)},
+		{builtinAtan, []Value{Int(0)}, Float(0)},
+		{builtinAtan, []Value{Int(1), Int(1)}, Float(math.Pi / 4)},
+	}
+
+	r := NewRuntime()
+	for _, test := range testData {
+		result, err := r.evalBuiltinProc(test.op, test.operands...)
+		if err != nil {
+			t.Errorf("\nerror: %s\ninput: {%s, %s}", err, test.op, test.operands)
+			break
+		}
+		if !reflect.DeepEqual(result, test.result) {
+			t.Errorf("\ninput: {%s, %s}\nexpect: {%s}\noutput: {%s}", test.op, test.operands, test.result, result)
+		}
+	}
+
+	errData := []struct {
+		op       *BuiltinProc
+		operands []Value
+		err      error
+	}{
+		{builtinDiv, []Value{Int(1), Int(0)}, errDivisionByZero},
+		{builtinMod, []Value{Int(1), Int(0)}, errDivisionByZero},
+		{builtinInexactToExact, []Value{Float(math.Inf(1))}, errNoExact},
+		{builtinAdd, []Value{Int(1), String("1")}, errTypeMismatch},
+	}
+	for _, test := range errData {
+		if _, err := r.evalBuiltinProc(test.op, test.operands...); err != test.err {
+			t.Errorf("\ninput: {%s, %s}\nexpect: %v\noutput: %v", test.op, test.operands, test.err, err)
+		}
+	}
+}
+
+func Test_evalRationalBuiltinProc(t *testing.T) {
+	// 10^400 is beyond the largest float
+	huge := bigInt("1" + strings.Repeat("0", 400))
+	inf, negInf := Float(math.Inf(1)), Float(math.Inf(-1))
+	testData := []struct {
+		op       *BuiltinProc
+		operands []Value
+		result   Value
+	}{
+		{builtinDiv, []Value{Int(6), Int(4)}, rat(3, 2)},
+		{builtinDiv, []Value{Int(-1), Int(3)}, rat(-1, 3)},
+		{builtinAdd, []Value{rat(1, 3), rat(2, 3)}, Int(1)},
+		{builtinSub, []Value{rat(1, 2)}, rat(-1, 2)},
+		{builtinMul, []Value{rat(3, 4), Int(4)}, Int(3)},
+		{builtinDiv, []Value{rat(1, 2), rat(1, 4)}, Int(2)},
+		{builtinAdd, []Value{rat(1, 2), Float(0.25)}, Float(0.75)},
+		{builtinEqNum, []Value{rat(1, 2), Float(0.5)}, Bool(true)},
+		{builtinLt, []Value{rat(1, 3), Float(0.34), rat(1, 2)}, Bool(true)},
+		{builtinGt, []Value{rat(1, 3), Float(1.0 / 3)}, Bool(true)},
+		{builtinLt, []Value{huge, inf}, Bool(true)},
+		{builtinLt, []Value{negInf, huge, inf}, Bool(true)},
+		{builtinLt, []Value{inf, huge}, Bool(false)},
+		{builtinEqNum, []Value{huge, inf}, Bool(false)},
+		{builtinEqNum, []Value{negInf, huge}, Bool(false)},
+		{builtinGt, []Value{huge, negInf}, Bool(true)},
+		{builtinGt, []Value{inf, huge, negInf}, Bool(true)},
+		{builtinGt, []Value{huge, inf}, Bool(false)},
+		{builtinLt, []Value{rat(1, 3), inf}, Bool(true)},
+		{builtinGt, []Value{rat(-1, 3), negInf}, Bool(true)},
+		{builtinEqNum, []Value{huge, Float(math.NaN())}, Bool(false)},
+		{builtinNumerator, []Value{rat(6, 4)}, Int(3)},
+		{builtinDenominator, []Value{rat(6, 4)}, Int(2)},
+		{builtinDenominator, []Value{Int(5)}, Int(1)},
+		{builtinNumerator, []Value{Float(0.75)}, Float(3)},
+		{builtinDenominator, []Value{Float(0.75)}, Float(4)},
+		{builtinIsExact, []Value{rat(1, 2)}, Bool(true)},
+		{builtinIsExact, []Value{Float(0.5)}, Bool(false)},
+		{builtinIsInexact, []Value{Float(0.5)}, Bool(true)},
+		{builtinExactToInexact, []Value{rat(1, 4)}, Float(0.25)},
+		{builtinInexactToExact, []Value{Float(2.5)}, rat(5, 2)},
+		{builtinFloor, []Value{rat(-7, 2)}, Int(-4)},
+		{builtinCeiling, []Value{rat(-7, 2)}, Int(-3)},
+		{builtinTruncate, []Value{rat(-7, 2)}, Int(-3)},
+		{builtinRound, []Value{rat(7, 2)}, Int(4)},
+		{builtinRound, []Value{rat(5, 2)}, Int(2)},
+		{builtinRound, []Value{rat(-5, 3)}, Int(-2)},
+		{builtinSqrt, []Value{rat(9, 4)}, rat(3, 2)},
+		{builtinSqrt, []Value{rat(1, 2)}, Float(math.Sqrt(0.5))},
+		{builtinExpt, []Value{rat(2, 3), Int(3)}, rat(8, 27)},
+		{builtinExpt, []Value{rat(2, 3), Int(-2)}, rat(9, 4)},
+	}
+
+	r := NewRuntime()
+	for _, test := range testData {
+		result, err := r.evalBuiltinProc(test.op, test.operands...)
+		if err != nil {
+			t.Errorf("\nerror: %s\ninput: {%s, %s}", err, test.op, test.operands)
+			break
+		}
+		if !reflect.DeepEqual(result, test.result) {
+			t.Errorf("\ninput: {%s, %s}\nexpect: {%s}\noutput: {%s}", test.op, test.operands, test.result, result)
+		}
+	}
+
+	errData := []struct {
+		op       *BuiltinProc
+		operands []Value
+		err      error
+	}{
+		{builtinDiv, []Value{rat(1, 2), Int(0)}, errDivisionByZero},
+		{builtinExpt, []Value{Int(0), Int(-1)}, errDivisionByZero},
+		{builtinNumerator, []Value{Float(math.NaN())}, errNoExact},
+	}
+	for _, test := range errData {
+		if _, err := r.evalBuiltinProc(test.op, test.operands...); err != test.err {
+			t.Errorf("\ninput: {%s, %s}\nexpect: %v\noutput: %v", test.op, test.operands, test.err, err)
+		}
+	}
+}
+
+func rat(a, b int64) Rat {
+	return Rat{big.NewRat(a, b)}
+}
+
+func bigInt(s string) BigInt {
+	n, _ := new(big.Int).SetString(s, 10)
+	return BigInt{n}
+}
@@ -0,0 +1,337 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dyzsr/mylisp/ast"
+	"github.com/dyzsr/mylisp/token"
+)
+
+var (
+	builtinIsString       = &BuiltinProc{name: "string?", proc: _isString, arity: fixed(1)}
+	builtinStringLength   = &BuiltinProc{name: "string-length", proc: _stringLength, arity: fixed(1)}
+	builtinStringAppend   = &BuiltinProc{name: "string-append", proc: _stringAppend, arity: atLeast(0)}
+	builtinSubstring      = &BuiltinProc{name: "substring", proc: _substring, arity: between(2, 3)}
+	builtinStringRef      = &BuiltinProc{name: "string-ref", proc: _stringRef, arity: fixed(2)}
+	builtinStringEq       = &BuiltinProc{name: "string=?", proc: _stringEq, arity: atLeast(1)}
+	builtinStringLt       = &BuiltinProc{name: "string<?", proc: _stringLt, arity: atLeast(1)}
+	builtinStringToSymbol = &BuiltinProc{name: "string->symbol", proc: _stringToSymbol, arity: fixed(1)}
+	builtinSymbolToString = &BuiltinProc{name: "symbol->string", proc: _symbolToString, arity: fixed(1)}
+	builtinNumberToString = &BuiltinProc{name: "number->string", proc: _numberToString, arity: between(1, 2)}
+	builtinStringToNumber = &BuiltinProc{name: "string->number", proc: _stringToNumber, arity: between(1, 2)}
+	builtinStringSplit    = &BuiltinProc{name: "string-split", proc: _stringSplit, arity: fixed(2)}
+	builtinStringJoin     = &BuiltinProc{name: "string-join", proc: _stringJoin, arity: between(1, 2)}
+	builtinStringToList   = &BuiltinProc{name: "string->list", proc: _stringToList, arity: fixed(1)}
+	builtinListToString   = &BuiltinProc{name: "list->string", proc: _listToString, arity: fixed(1)}
+)
+
+var (
+	errIndexOutOfRange = errors.New("index out of range")
+	errBadRadix        = errors.New("bad radix")
+)
+
+func toStrings(args []Value) ([]String, error) {
+	var strs []String
+	for _, arg := range args {
+		str, ok := arg.(String)
+		if !ok {
+			return nil, errTypeMismatch
+		}
+		strs = append(strs, str)
+	}
+	return strs, nil
+}
+
+// toIndex converts arg to an index within [0, size].
+func toIndex(arg Value, size int) (int, error) {
+	if _, ok := arg.(BigInt); ok {
+		return 0, errIndexOutOfRange
+	}
+	num, ok := arg.(Int)
+	if !ok {
+		return 0, errTypeMismatch
+	}
+	if num < 0 || num > Int(size) {
+		return 0, errIndexOutOfRange
+	}
+	return int(num), nil
+}
+
+// toRadix converts the optional radix argument of a number conversion.
+func toRadix(args []Value) (int, error) {
+	if len(args) == 0 {
+		return 10, nil
+	}
+	radix, ok := args[0].(Int)
+	if !ok {
+		return 0, errTypeMismatch
+	}
+	if radix < 2 || radix > 36 {
+		return 0, errBadRadix
+	}
+	return int(radix), nil
+}
+
+func _isString(args ...Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, errArityMismatch
+	}
+	_, ok := args[0].(String)
+	return Bool(ok), nil
+}
+
+func _stringLength(args ...Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, errArityMismatch
+	}
+	strs, err := toStrings(args)
+	if err != nil {
+		return nil, err
+	}
+	return Int(utf8.RuneCountInString(string(strs[0]))), nil
+}
+
+func _stringAppend(args ...Value) (Value, error) {
+	strs, err := toStrings(args)
+	if err != nil {
+		return nil, err
+	}
+	var sb strings.Builder
+	for _, str := range strs {
+		sb.WriteString(string(str))
+	}
+	return String(sb.String()), nil
+}
+
+func _substring(args ...Value) (Value, error) {
+	if len(args) != 2 && len(args) != 3 {
+		return nil, errArityMismatch
+	}
+	str, ok := args[0].(String)
+	if !ok {
+		return nil, errTypeMismatch
+	}
+	runes := []rune(string(str))
+	start, err := toIndex(args[1], len(runes))
+	if err != nil {
+		return nil, err
+	}
+	end := len(runes)
+	if len(args) == 3 {
+		if end, err = toIndex(args[2], len(runes)); err != nil {
+			return nil, err
+		}
+	}
+	if start > end {
+		return nil, errIndexOutOfRange
+	}
+	return String(runes[start:end]), nil
+}
+
+func _stringRef(args ...Value) (Value, error) {
+	if len(args) != 2 {
+		return nil, errArityMismatch
+	}
+	str, ok := args[0].(String)
+	if !ok {
+		return nil, errTypeMismatch
+	}
+	runes := []rune(string(str))
+	k, err := toIndex(args[1], len(runes)-1)
+	if err != nil {
+		return nil, err
+	}
+	return Char(runes[k]), nil
+}
+
+func _stringEq(args ...Value) (Value, error) {
+	if len(args) == 0 {
+		return nil, errArityMismatch
+	}
+	strs, err := toStrings(args)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 1; i < len(strs); i++ {
+		if strs[i-1] != strs[i] {
+			return Bool(false), nil
+		}
+	}
+	return Bool(true), nil
+}
+
+func _stringLt(args ...Value) (Value, error) {
+	if len(args) == 0 {
+		return nil, errArityMismatch
+	}
+	strs, err := toStrings(args)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 1; i < len(strs); i++ {
+		if strs[i-1] >= strs[i] {
+			return Bool(false), nil
+		}
+	}
+	return Bool(true), nil
+}
+
+func _stringToSymbol(args ...Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, errArityMismatch
+	}
+	str, ok := args[0].(String)
+	if !ok {
+		return nil, errTypeMismatch
+	}
+	return Symbol{symbolMap(string(str))}, nil
+}
+
+func _symbolToString(args ...Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, errArityMismatch
+	}
+	sym, ok := args[0].(Symbol)
+	if !ok {
+		return nil, errTypeMismatch
+	}
+	return String(*sym.string), nil
+}
+
+func _numberToString(args ...Value) (Value, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, errArityMismatch
+	}
+	if err := checkNumbers(args[:1]); err != nil {
+		return nil, err
+	}
+	radix, err := toRadix(args[1:])
+	if err != nil {
+		return nil, err
+	}
+	switch num := args[0].(type) {
+	case Int:
+		return String(strconv.FormatInt(int64(num), radix)), nil
+	case BigInt:
+		return String(num.Text(radix)), nil
+	case Rat:
+		return String(num.Num().Text(radix) + "/" + num.Denom().Text(radix)), nil
+	default:
+		if radix != 10 {
+			return nil, errBadRadix
+		}
+		return String(fmt.Sprint(num)), nil
+	}
+}
+
+// _stringToNumber returns false if the string is not a valid number.
+func _stringToNumber(args ...Value) (Value, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, errArityMismatch
+	}
+	str, ok := args[0].(String)
+	if !ok {
+		return nil, errTypeMismatch
+	}
+	radix, err := toRadix(args[1:])
+	if err != nil {
+		return nil, err
+	}
+	expr, err := token.ParseNumber(string(str), radix)
+	if err != nil {
+		return Bool(false), nil
+	}
+	switch lit := expr.(type) {
+	case *ast.IntLit:
+		return Int(lit.Value), nil
+	case *ast.BigIntLit:
+		return BigInt{lit.Value}, nil
+	case *ast.RatLit:
+		return Rat{lit.Value}, nil
+	case *ast.FloatLit:
+		return Float(lit.Value), nil
+	}
+	return Bool(false), nil
+}
+
+func _stringSplit(args ...Value) (Value, error) {
+	if len(args) != 2 {
+		return nil, errArityMismatch
+	}
+	strs, err := toStrings(args)
+	if err != nil {
+		return nil, err
+	}
+	var items []Value
+	for _, item := range strings.Split(string(strs[0]), string(strs[1])) {
+		items = append(items, String(item))
+	}
+	return _list(items...)
+}
+
+// _stringJoin joins a list of strings, separated by a space by default.
+func _stringJoin(args ...Value) (Value, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, errArityMismatch
+	}
+	items, err := listToSlice(args[0])
+	if err != nil {
+		return nil, err
+	}
+	strs, err := toStrings(items)
+	if err != nil {
+		return nil, err
+	}
+	sep := String(" ")
+	if len(args) == 2 {
+		var ok bool
+		if sep, ok = args[1].(String); !ok {
+			return nil, errTypeMismatch
+		}
+	}
+	var substr []string
+	for _, str := range strs {
+		substr = append(substr, string(str))
+	}
+	return String(strings.Join(substr, string(sep))), nil
+}
+
+func _stringToList(args ...Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, errArityMismatch
+	}
+	str, ok := args[0].(String)
+	if !ok {
+		return nil, errTypeMismatch
+	}
+	var chars []Value
+	for _, ch := range string(str) {
+		chars = append(chars, Char(ch))
+	}
+	return _list(chars...)
+}
+
+func _listToString(args ...Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, errArityMismatch
+	}
+	items, err := listToSlice(args[0])
+	if err != nil {
+		return nil, err
+	}
+	chars, err := toChars(items)
+	if err != nil {
+		return nil, err
+	}
+	runes := make([]rune, len(chars))
+	for i, ch := range chars {
+		runes[i] = rune(ch)
+	}
+	return String(runes), nil
+}
@@ -0,0 +1,177 @@
+package runtime
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_evalStringBuiltinProc(t *testing.T) {
+	testData := []struct {
+		op       *BuiltinProc
+		operands []Value
+		result   Value
+	}{
+		{
+			builtinIsString,
+			[]Value{String("abc")},
+			Bool(true),
+		},
+		{
+			builtinIsString,
+			[]Value{Symbol{symbolMap("abc")}},
+			Bool(false),
+		},
+		{
+			builtinStringLength,
+			[]Value{String("héllo")},
+			Int(5),
+		},
+		{
+			builtinStringAppend,
+			nil,
+			String(""),
+		},
+		{
+			builtinStringAppend,
+			[]Value{String("ab"), String(""), String("cd")},
+			String("abcd"),
+		},
+		{
+			builtinSubstring,
+			[]Value{String("héllo"), Int(1), Int(3)},
+			String("él"),
+		},
+		{
+			builtinSubstring,
+			[]Value{String("hello"), Int(2)},
+			String("llo"),
+		},
+		{
+			builtinStringRef,
+			[]Value{String("héllo"), Int(1)},
+			Char('é'),
+		},
+		{
+			builtinStringEq,
+			[]Value{String("abc"), String("abc"), String("abc")},
+			Bool(true),
+		},
+		{
+			builtinStringEq,
+			[]Value{String("abc"), String("abd")},
+			Bool(false),
+		},
+		{
+			builtinStringLt,
+			[]Value{String("abc"), String("abd"), String("b")},
+			Bool(true),
+		},
+		{
+			builtinStringLt,
+			[]Value{String("abc"), String("abc")},
+			Bool(false),
+		},
+		{
+			builtinStringToSymbol,
+			[]Value{String("abc")},
+			Symbol{symbolMap("abc")},
+		},
+		{
+			builtinSymbolToString,
+			[]Value{Symbol{symbolMap("abc")}},
+			String("abc"),
+		},
+		{
+			builtinNumberToString,
+			[]Value{Int(-255)},
+			String("-255"),
+		},
+		{
+			builtinNumberToString,
+			[]Value{Int(255), Int(16)},
+			String("ff"),
+		},
+		{
+			builtinStringToNumber,
+			[]Value{String("-255")},
+			Int(-255),
+		},
+		{
+			builtinStringToNumber,
+			[]Value{String("1010"), Int(2)},
+			Int(10),
+		},
+		{
+			builtinNumberToString,
+			[]Value{rat(-3, 4), Int(2)},
+			String("-11/100"),
+		},
+		{
+			builtinStringToNumber,
+			[]Value{String("6/4")},
+			rat(3, 2),
+		},
+		{
+			builtinStringToNumber,
+			[]Value{String("#xff"), Int(2)},
+			Int(255),
+		},
+		{
+			builtinStringToNumber,
+			[]Value{String("#e0.5")},
+			rat(1, 2),
+		},
+		{
+			builtinStringToNumber,
+			[]Value{String("12a")},
+			Bool(false),
+		},
+		{
+			builtinStringSplit,
+			[]Value{String("a,b,,c"), String(",")},
+			&Pair{first: String("a"), second: &Pair{first: String("b"), second: &Pair{first: String(""), second: &Pair{first: String("c"), second: Nil{}}}}},
+		},
+		{
+			builtinStringJoin,
+			[]Value{&Pair{first: String("a"), second: &Pair{first: String("b"), second: Nil{}}}},
+			String("a b"),
+		},
+		{
+			builtinStringJoin,
+			[]Value{&Pair{first: String("a"), second: &Pair{first: String("b"), second: Nil{}}}, String(", ")},
+			String("a, b"),
+		},
+		{
+			builtinStringJoin,
+			[]Value{Nil{}, String(", ")},
+			String(""),
+		},
+		{
+			builtinStringToList,
+			[]Value{String("ab")},
+			&Pair{first: Char('a'), second: &Pair{first: Char('b'), second: Nil{}}},
+		},
+		{
+			builtinListToString,
+			[]Value{&Pair{first: Char('a'), second: &Pair{first: Char('é'), second: Nil{}}}},
+			String("aé"),
+		},
+		{
+			builtinListToString,
+			[]Value{Nil{}},
+			String(""),
+		},
+	}
+
+	r := NewRuntime()
+	for _, test := range testData {
+		result, err := r.evalBuiltinProc(test.op, test.operands...)
+		if err != nil {
+			t.Errorf("\nerror: %s\ninput: {%s, %s}", err, test.op, test.operands)
+			break
+		}
+		if !reflect.DeepEqual(result, test.result) {
+			t.Errorf("\ninput: {%s, %s}\nexpect: {%s}\noutput: {%s}", test.op, test.operands, test.result, result)
+		}
+	}
+}
@@ -96,26 +96,6 @@ func Test_evalBuiltinProc(t *testing.T) {
 			[]Value{Int(123), Int(456), Int(321)},
 			Bool(false),
 		},
-		{
-			builtinAnd,
-			[]Value{Bool(true), Bool(false)},
-			Bool(false),
-		},
-		{
-			builtinAnd,
-			[]Value{Bool(true), Bool(true)},
-			Bool(true),
-		},
-		{
-			builtinOr,
-			[]Value{Bool(true), Bool(false)},
-			Bool(true),
-		},
-		{
-			builtinOr,
-			[]Value{Bool(false), Bool(false)},
-			Bool(false),
-		},
 		{
 			builtinNot,
 			[]Value{Bool(false)},
@@ -126,6 +106,11 @@ func Test_evalBuiltinProc(t *testing.T) {
 			[]Value{Bool(true)},
 			Bool(false),
 		},
+		{
+			builtinNot,
+			[]Value{Nil{}},
+			Bool(false),
+		},
 		{
 			builtinCons,
 			[]Value{Int(1), Int(2)},
@@ -185,3 +170,32 @@ func Test_evalBuiltinProc(t *testing.T) {
 		}
 	}
 }
+
+func Test_mutablePair(t *testing.T) {
+	r := NewRuntime()
+	// (1 2) becomes (0 2 . (0 2 . ...))
+	list := &Pair{first: Int(1), second: &Pair{first: Int(2), second: Nil{}}}
+	if _, err := r.evalBuiltinProc(builtinSetCar, list, Int(0)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.evalBuiltinProc(builtinSetCdr, list.second, list); err != nil {
+		t.Fatal(err)
+	}
+	if list.first != Int(0) || list.second.(*Pair).second != list {
+		t.Errorf("output: %s", list)
+	}
+	if _, err := r.evalBuiltinProc(builtinSetCar, Nil{}, Int(0)); err != errTypeMismatch {
+		t.Errorf("expect: %v, output: %v", errTypeMismatch, err)
+	}
+
+	// an unrolled copy of the cycle is equal to it
+	other := &Pair{first: Int(0), second: &Pair{first: Int(2), second: nil}}
+	other.second.(*Pair).second = &Pair{first: Int(0), second: &Pair{first: Int(2), second: other}}
+	if result, _ := r.evalBuiltinProc(builtinEqual, list, other); result != Bool(true) {
+		t.Errorf("cyclic lists: expect equal")
+	}
+	other.first = Int(1)
+	if result, _ := r.evalBuiltinProc(builtinEqual, list, other); result != Bool(false) {
+		t.Errorf("cyclic lists: expect not equal")
+	}
+}
@@ -0,0 +1,131 @@
+package runtime
+
+import "math"
+
+var (
+	builtinIsNull      = &BuiltinProc{name: "null?", proc: _isNull, arity: fixed(1)}
+	builtinIsPair      = &BuiltinProc{name: "pair?", proc: _isPair, arity: fixed(1)}
+	builtinIsList      = &BuiltinProc{name: "list?", proc: _isList, arity: fixed(1)}
+	builtinIsSymbol    = &BuiltinProc{name: "symbol?", proc: _isSymbol, arity: fixed(1)}
+	builtinIsNumber    = &BuiltinProc{name: "number?", proc: _isNumber, arity: fixed(1)}
+	builtinIsInteger   = &BuiltinProc{name: "integer?", proc: _isInteger, arity: fixed(1)}
+	builtinIsRational  = &BuiltinProc{name: "rational?", proc: _isRational, arity: fixed(1)}
+	builtinIsBoolean   = &BuiltinProc{name: "boolean?", proc: _isBoolean, arity: fixed(1)}
+	builtinIsProcedure = &BuiltinProc{name: "procedure?", proc: _isProcedure, arity: fixed(1)}
+	builtinTypeOf      = &BuiltinProc{name: "type-of", proc: _typeOf, arity: fixed(1)}
+	builtinProcArity   = &BuiltinProc{name: "procedure-arity", proc: _procedureArity, arity: fixed(1)}
+)
+
+// predicate makes a type predicate.
+func predicate(f func(Value) bool) func(...Value) (Value, error) {
+	return func(args ...Value) (Value, error) {
+		if len(args) != 1 {
+			return nil, errArityMismatch
+		}
+		return Bool(f(args[0])), nil
+	}
+}
+
+var (
+	_isNull = predicate(func(v Value) bool {
+		_, ok := v.(Nil)
+		return ok
+	})
+	_isPair = predicate(func(v Value) bool {
+		_, ok := v.(*Pair)
+		return ok
+	})
+	_isSymbol = predicate(func(v Value) bool {
+		_, ok := v.(Symbol)
+		return ok
+	})
+	_isBoolean = predicate(func(v Value) bool {
+		_, ok := v.(Bool)
+		return ok
+	})
+	_isProcedure = predicate(func(v Value) bool {
+		_, ok := procArity(v)
+		return ok
+	})
+	_isNumber = predicate(isNumber)
+
+	// _isInteger is true for the floats with integral values as well.
+	_isInteger = predicate(func(v Value) bool {
+		if x, ok := v.(Float); ok {
+			return isFinite(x) && x == Float(math.Trunc(float64(x)))
+		}
+		return isExactInteger(v)
+	})
+	// _isRational is true for the exact numbers and the finite floats.
+	_isRational = predicate(func(v Value) bool {
+		if x, ok := v.(Float); ok {
+			return isFinite(x)
+		}
+		return isExact(v)
+	})
+)
+
+var _isList = predicate(isList)
+
+// isList reports whether a value is a proper list, which is neither improper
+// nor cyclic.
+func isList(v Value) bool {
+	// the fast one moves two pairs at a time, and meets the slow one if the
+	// list is cyclic
+	slow, fast := v, v
+	for {
+		for i := 0; i < 2; i++ {
+			switch p := fast.(type) {
+			case Nil:
+				return true
+			case *Pair:
+				fast = p.second
+			default:
+				return false
+			}
+		}
+		slow = slow.(*Pair).second
+		if slow == fast {
+			return false
+		}
+	}
+}
+
+// _typeOf returns the name of the kind of a value as a symbol.
+func _typeOf(args ...Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, errArityMismatch
+	}
+	return Symbol{symbolMap(args[0].Type().Kind().String())}, nil
+}
+
+func procArity(v Value) (arity, bool) {
+	switch proc := v.(type) {
+	case *BuiltinProc:
+		return proc.arity, true
+	case *Proc:
+		return proc.arity(), true
+	}
+	return arity{}, false
+}
+
+// _procedureArity returns the number of arguments a procedure takes, or a
+// pair of the least and the most numbers of them if they vary. The most one
+// is false if there is no upper bound.
+func _procedureArity(args ...Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, errArityMismatch
+	}
+	a, ok := procArity(args[0])
+	if !ok {
+		return nil, errTypeMismatch
+	}
+	if a.min == a.max {
+		return Int(a.min), nil
+	}
+	var max Value = Bool(false)
+	if a.max >= 0 {
+		max = Int(a.max)
+	}
+	return &Pair{first: Int(a.min), second: max}, nil
+}
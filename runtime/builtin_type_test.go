@@ -0,0 +1,110 @@
+package runtime
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/dyzsr/mylisp/ast"
+)
+
+func Test_evalTypeBuiltinProc(t *testing.T) {
+	cycle := &Pair{first: Int(1)}
+	cycle.second = &Pair{first: Int(2), second: cycle}
+	list, _ := _list(Int(1), Int(2), Int(3))
+	lambda := &Proc{LambdaExpr: &ast.LambdaExpr{Args: []*ast.Ident{ast.NewIdent("x"), ast.NewIdent("y")}}}
+
+	testData := []struct {
+		op       *BuiltinProc
+		operands []Value
+		result   Value
+	}{
+		{builtinIsNull, []Value{Nil{}}, Bool(true)},
+		{builtinIsNull, []Value{list}, Bool(false)},
+		{builtinIsPair, []Value{list}, Bool(true)},
+		{builtinIsPair, []Value{Nil{}}, Bool(false)},
+		{builtinIsList, []Value{Nil{}}, Bool(true)},
+		{builtinIsList, []Value{list}, Bool(true)},
+		{builtinIsList, []Value{&Pair{first: Int(1), second: Int(2)}}, Bool(false)},
+		{builtinIsList, []Value{cycle}, Bool(false)},
+		{builtinIsList, []Value{Int(1)}, Bool(false)},
+		{builtinIsSymbol, []Value{Symbol{symbolMap("a")}}, Bool(true)},
+		{builtinIsSymbol, []Value{String("a")}, Bool(false)},
+		{builtinIsNumber, []Value{rat(1, 2)}, Bool(true)},
+		{builtinIsNumber, []Value{String("1")}, Bool(false)},
+		{builtinIsInteger, []Value{bigInt("100000000000000000000")}, Bool(true)},
+		{builtinIsInteger, []Value{Float(2)}, Bool(true)},
+		{builtinIsInteger, []Value{Float(2.5)}, Bool(false)},
+		{builtinIsInteger, []Value{rat(1, 2)}, Bool(false)},
+		{builtinIsRational, []Value{rat(1, 2)}, Bool(true)},
+		{builtinIsRational, []Value{Float(0.5)}, Bool(true)},
+		{builtinIsRational, []Value{Float(math.Inf(1))}, Bool(false)},
+		{builtinIsBoolean, []Value{Bool(false)}, Bool(true)},
+		{builtinIsBoolean, []Value{Nil{}}, Bool(false)},
+		{builtinIsProcedure, []Value{builtinCar}, Bool(true)},
+		{builtinIsProcedure, []Value{lambda}, Bool(true)},
+		{builtinIsProcedure, []Value{Symbol{symbolMap("car")}}, Bool(false)},
+		{builtinTypeOf, []Value{Int(1)}, Symbol{symbolMap("integer")}},
+		{builtinTypeOf, []Value{rat(1, 2)}, Symbol{symbolMap("rational")}},
+		{builtinTypeOf, []Value{Nil{}}, Symbol{symbolMap("nil")}},
+		{builtinTypeOf, []Value{list}, Symbol{symbolMap("pair")}},
+		{builtinTypeOf, []Value{&HashTable{}}, Symbol{symbolMap("hash-table")}},
+		{builtinTypeOf, []Value{builtinCar}, Symbol{symbolMap("builtin")}},
+		{builtinTypeOf, []Value{lambda}, Symbol{symbolMap("procedure")}},
+		{builtinProcArity, []Value{builtinCar}, Int(1)},
+		{builtinProcArity, []Value{builtinSubstring}, &Pair{first: Int(2), second: Int(3)}},
+		{builtinProcArity, []Value{builtinAdd}, &Pair{first: Int(0), second: Bool(false)}},
+		{builtinProcArity, []Value{lambda}, Int(2)},
+	}
+
+	r := NewRuntime()
+	for _, test := range testData {
+		result, err := r.evalBuiltinProc(test.op, test.operands...)
+		if err != nil {
+			t.Errorf("\nerror: %s\ninput: {%s, %s}", err, test.op, test.operands)
+			break
+		}
+		if !reflect.DeepEqual(result, test.result) {
+			t.Errorf("\ninput: {%s, %s}\nexpect: {%s}\noutput: {%s}", test.op, test.operands, test.result, result)
+		}
+	}
+
+	if _, err := r.evalBuiltinProc(builtinProcArity, Int(1)); err != errTypeMismatch {
+		t.Errorf("expect %v, got %v", errTypeMismatch, err)
+	}
+}
+
+// Test_builtinArity checks that every built-in rejects the numbers of
+// arguments out of its declared arity.
+func Test_builtinArity(t *testing.T) {
+	r := NewRuntime()
+	args := make([]Value, 8)
+	for i := range args {
+		args[i] = Symbol{symbolMap("x")}
+	}
+	for name, v := range builtinVariables() {
+		proc, ok := v.(*BuiltinProc)
+		if !ok {
+			continue
+		}
+		a := proc.arity
+		if a.min > 0 {
+			if _, err := r.evalBuiltinProc(proc, args[:a.min-1]...); err != errArityMismatch {
+				t.Errorf("%s: expect %v for %d arguments, got %v", name, errArityMismatch, a.min-1, err)
+			}
+		}
+		if a.max >= 0 {
+			if _, err := r.evalBuiltinProc(proc, args[:a.max+1]...); err != errArityMismatch {
+				t.Errorf("%s: expect %v for %d arguments, got %v", name, errArityMismatch, a.max+1, err)
+			}
+		}
+		for _, n := range []int{a.min, a.max} {
+			if n < 0 {
+				continue
+			}
+			if _, err := r.evalBuiltinProc(proc, args[:n]...); err == errArityMismatch {
+				t.Errorf("%s: unexpected %v for %d arguments", name, err, n)
+			}
+		}
+	}
+}
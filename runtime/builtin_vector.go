@@ -0,0 +1,231 @@
+package runtime
+
+var (
+	builtinIsVector      = &BuiltinProc{name: "vector?", proc: _isVector, arity: fixed(1)}
+	builtinMakeVector    = &BuiltinProc{name: "make-vector", proc: _makeVector, arity: between(1, 2)}
+	builtinVector        = &BuiltinProc{name: "vector", proc: _vector, arity: atLeast(0)}
+	builtinVectorLength  = &BuiltinProc{name: "vector-length", proc: _vectorLength, arity: fixed(1)}
+	builtinVectorRef     = &BuiltinProc{name: "vector-ref", proc: _vectorRef, arity: fixed(2)}
+	builtinVectorSet     = &BuiltinProc{name: "vector-set!", proc: _vectorSet, arity: fixed(3)}
+	builtinVectorToList  = &BuiltinProc{name: "vector->list", proc: _vectorToList, arity: between(1, 3)}
+	builtinListToVector  = &BuiltinProc{name: "list->vector", proc: _listToVector, arity: fixed(1)}
+	builtinVectorFill    = &BuiltinProc{name: "vector-fill!", proc: _vectorFill, arity: between(2, 4)}
+	builtinVectorCopy    = &BuiltinProc{name: "vector-copy", proc: _vectorCopy, arity: between(1, 3)}
+	builtinVectorMap     = &BuiltinProc{name: "vector-map", call: _vectorMap, arity: atLeast(2)}
+	builtinVectorForEach = &BuiltinProc{name: "vector-for-each", call: _vectorForEach, arity: atLeast(2)}
+)
+
+func toVectors(args []Value) ([]*Vector, error) {
+	var vecs []*Vector
+	for _, arg := range args {
+		vec, ok := arg.(*Vector)
+		if !ok {
+			return nil, errTypeMismatch
+		}
+		vecs = append(vecs, vec)
+	}
+	return vecs, nil
+}
+
+// toRange converts the optional start and end arguments selecting a part of
+// a sequence of the given size.
+func toRange(args []Value, size int) (start, end int, err error) {
+	end = size
+	if len(args) > 0 {
+		if start, err = toIndex(args[0], size); err != nil {
+			return 0, 0, err
+		}
+	}
+	if len(args) > 1 {
+		if end, err = toIndex(args[1], size); err != nil {
+			return 0, 0, err
+		}
+	}
+	if start > end {
+		return 0, 0, errIndexOutOfRange
+	}
+	return start, end, nil
+}
+
+func _isVector(args ...Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, errArityMismatch
+	}
+	_, ok := args[0].(*Vector)
+	return Bool(ok), nil
+}
+
+// _makeVector makes a vector of k items, which are all fill if given.
+func _makeVector(args ...Value) (Value, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, errArityMismatch
+	}
+	k, err := toLength(args[0])
+	if err != nil {
+		return nil, err
+	}
+	var fill Value = Nil{}
+	if len(args) == 2 {
+		fill = args[1]
+	}
+	items := make([]Value, k)
+	for i := range items {
+		items[i] = fill
+	}
+	return &Vector{items: items}, nil
+}
+
+func _vector(args ...Value) (Value, error) {
+	return &Vector{items: append([]Value{}, args...)}, nil
+}
+
+func _vectorLength(args ...Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, errArityMismatch
+	}
+	vec, ok := args[0].(*Vector)
+	if !ok {
+		return nil, errTypeMismatch
+	}
+	return Int(len(vec.items)), nil
+}
+
+func _vectorRef(args ...Value) (Value, error) {
+	if len(args) != 2 {
+		return nil, errArityMismatch
+	}
+	vec, ok := args[0].(*Vector)
+	if !ok {
+		return nil, errTypeMismatch
+	}
+	k, err := toIndex(args[1], len(vec.items)-1)
+	if err != nil {
+		return nil, err
+	}
+	return vec.items[k], nil
+}
+
+func _vectorSet(args ...Value) (Value, error) {
+	if len(args) != 3 {
+		return nil, errArityMismatch
+	}
+	vec, ok := args[0].(*Vector)
+	if !ok {
+		return nil, errTypeMismatch
+	}
+	k, err := toIndex(args[1], len(vec.items)-1)
+	if err != nil {
+		return nil, err
+	}
+	vec.items[k] = args[2]
+	return Nil{}, nil
+}
+
+func _vectorToList(args ...Value) (Value, error) {
+	if len(args) < 1 || len(args) > 3 {
+		return nil, errArityMismatch
+	}
+	vec, ok := args[0].(*Vector)
+	if !ok {
+		return nil, errTypeMismatch
+	}
+	start, end, err := toRange(args[1:], len(vec.items))
+	if err != nil {
+		return nil, err
+	}
+	return _list(vec.items[start:end]...)
+}
+
+func _listToVector(args ...Value) (Value, error) {
+	if len(args) != 1 {
+		return nil, errArityMismatch
+	}
+	items, err := listToSlice(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return &Vector{items: append([]Value{}, items...)}, nil
+}
+
+func _vectorFill(args ...Value) (Value, error) {
+	if len(args) < 2 || len(args) > 4 {
+		return nil, errArityMismatch
+	}
+	vec, ok := args[0].(*Vector)
+	if !ok {
+		return nil, errTypeMismatch
+	}
+	start, end, err := toRange(args[2:], len(vec.items))
+	if err != nil {
+		return nil, err
+	}
+	for i := start; i < end; i++ {
+		vec.items[i] = args[1]
+	}
+	return Nil{}, nil
+}
+
+// _vectorCopy makes a new vector of the items from start to end.
+func _vectorCopy(args ...Value) (Value, error) {
+	if len(args) < 1 || len(args) > 3 {
+		return nil, errArityMismatch
+	}
+	vec, ok := args[0].(*Vector)
+	if !ok {
+		return nil, errTypeMismatch
+	}
+	start, end, err := toRange(args[1:], len(vec.items))
+	if err != nil {
+		return nil, err
+	}
+	return &Vector{items: append([]Value{}, vec.items[start:end]...)}, nil
+}
+
+// vectorWalk calls proc on the items of the vectors at each index, up to the
+// length of the shortest vector.
+func vectorWalk(r *Runtime, args []Value, f func(int, Value)) error {
+	if len(args) < 2 {
+		return errArityMismatch
+	}
+	vecs, err := toVectors(args[1:])
+	if err != nil {
+		return err
+	}
+	size := len(vecs[0].items)
+	for _, vec := range vecs {
+		if len(vec.items) < size {
+			size = len(vec.items)
+		}
+	}
+
+	operands := make([]Value, len(vecs))
+	for i := 0; i < size; i++ {
+		for j, vec := range vecs {
+			operands[j] = vec.items[i]
+		}
+		result, err := r.apply(args[0], operands...)
+		if err != nil {
+			return err
+		}
+		f(i, result)
+	}
+	return nil
+}
+
+func _vectorMap(r *Runtime, args ...Value) (Value, error) {
+	items := []Value{}
+	err := vectorWalk(r, args, func(i int, result Value) {
+		items = append(items, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Vector{items: items}, nil
+}
+
+func _vectorForEach(r *Runtime, args ...Value) (Value, error) {
+	if err := vectorWalk(r, args, func(int, Value) {}); err != nil {
+		return nil, err
+	}
+	return Nil{}, nil
+}
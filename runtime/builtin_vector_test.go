@@ -0,0 +1,86 @@
+package runtime
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_evalVectorBuiltinProc(t *testing.T) {
+	vec := func(items ...Value) *Vector {
+		return &Vector{items: append([]Value{}, items...)}
+	}
+
+	testData := []struct {
+		op       *BuiltinProc
+		operands []Value
+		result   Value
+	}{
+		{builtinIsVector, []Value{vec()}, Bool(true)},
+		{builtinIsVector, []Value{Nil{}}, Bool(false)},
+		{builtinMakeVector, []Value{Int(2), Char('a')}, vec(Char('a'), Char('a'))},
+		{builtinMakeVector, []Value{Int(0)}, vec()},
+		{builtinVector, []Value{Int(1), String("b")}, vec(Int(1), String("b"))},
+		{builtinVector, nil, vec()},
+		{builtinVectorLength, []Value{vec(Int(1), Int(2))}, Int(2)},
+		{builtinVectorRef, []Value{vec(Int(1), Int(2)), Int(1)}, Int(2)},
+		{builtinVectorToList, []Value{vec(Int(1), Int(2))}, &Pair{first: Int(1), second: &Pair{first: Int(2), second: Nil{}}}},
+		{builtinVectorToList, []Value{vec(Int(1), Int(2), Int(3)), Int(1), Int(2)}, &Pair{first: Int(2), second: Nil{}}},
+		{builtinListToVector, []Value{&Pair{first: Int(1), second: Nil{}}}, vec(Int(1))},
+		{builtinListToVector, []Value{Nil{}}, vec()},
+		{builtinVectorCopy, []Value{vec(Int(1), Int(2), Int(3)), Int(1)}, vec(Int(2), Int(3))},
+		{builtinVectorCopy, []Value{vec(Int(1), Int(2), Int(3)), Int(0), Int(2)}, vec(Int(1), Int(2))},
+		{builtinVectorMap, []Value{builtinAdd, vec(Int(1), Int(2)), vec(Int(10), Int(20), Int(30))}, vec(Int(11), Int(22))},
+		{builtinVectorMap, []Value{builtinAdd, vec()}, vec()},
+		{builtinVectorForEach, []Value{builtinAdd, vec(Int(1))}, Nil{}},
+	}
+
+	r := NewRuntime()
+	for _, test := range testData {
+		result, err := r.evalBuiltinProc(test.op, test.operands...)
+		if err != nil {
+			t.Errorf("\nerror: %s\ninput: {%s, %s}", err, test.op, test.operands)
+			break
+		}
+		if !reflect.DeepEqual(result, test.result) {
+			t.Errorf("\ninput: {%s, %s}\nexpect: {%s}\noutput: {%s}", test.op, test.operands, test.result, result)
+		}
+	}
+
+	errData := []struct {
+		op       *BuiltinProc
+		operands []Value
+		err      error
+	}{
+		{builtinMakeVector, []Value{Int(-1)}, errIndexOutOfRange},
+		{builtinMakeVector, []Value{Int(100000000000000)}, errTooLong},
+		{builtinMakeVector, []Value{bigInt("100000000000000000000"), Int(0)}, errTooLong},
+		{builtinVectorRef, []Value{vec(Int(1)), Int(1)}, errIndexOutOfRange},
+		{builtinVectorRef, []Value{vec(), Int(0)}, errIndexOutOfRange},
+		{builtinVectorRef, []Value{Nil{}, Int(0)}, errTypeMismatch},
+		{builtinVectorSet, []Value{vec(Int(1)), Int(-1), Int(0)}, errIndexOutOfRange},
+		{builtinVectorCopy, []Value{vec(Int(1), Int(2)), Int(2), Int(1)}, errIndexOutOfRange},
+		{builtinListToVector, []Value{Int(1)}, errTypeMismatch},
+		{builtinVectorMap, []Value{Int(1), vec(Int(1))}, errNotProcedure},
+		{builtinVectorMap, []Value{builtinAdd}, errArityMismatch},
+	}
+	for _, test := range errData {
+		if _, err := r.evalBuiltinProc(test.op, test.operands...); err != test.err {
+			t.Errorf("\ninput: {%s, %s}\nexpect: %v\noutput: %v", test.op, test.operands, test.err, err)
+		}
+	}
+}
+
+func Test_vectorMutation(t *testing.T) {
+	v := &Vector{items: []Value{Int(1), Int(2), Int(3)}}
+	r := NewRuntime()
+	if _, err := r.evalBuiltinProc(builtinVectorSet, v, Int(0), String("x")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.evalBuiltinProc(builtinVectorFill, v, Int(0), Int(1)); err != nil {
+		t.Fatal(err)
+	}
+	expect := &Vector{items: []Value{String("x"), Int(0), Int(0)}}
+	if !reflect.DeepEqual(v, expect) {
+		t.Errorf("expect: %s, output: %s", expect, v)
+	}
+}
@@ -1,5 +1,7 @@
 package runtime
 
+import "github.com/dyzsr/mylisp/ast"
+
 type callstack struct {
 	frames []stackframe
 }
@@ -14,17 +16,20 @@ func (s *callstack) empty() bool {
 	return len(s.frames) == 0
 }
 
-func (s *callstack) push(proc *Proc, params []Value) {
-	s.frames = append(s.frames, stackframe{proc: proc, params: params})
+func (s *callstack) push(proc *Proc, params []Value, site *ast.Pos) {
+	s.frames = append(s.frames, stackframe{proc: proc, params: params, site: site})
 }
 
-func (s *callstack) modify(proc *Proc, params []Value) {
+func (s *callstack) modify(proc *Proc, params []Value, site *ast.Pos) {
 	if s.empty() {
 		panic("callstack is empty")
 	}
-	s.frames[len(s.frames)-1] = stackframe{
+	top := &s.frames[len(s.frames)-1]
+	*top = stackframe{
 		proc:     proc,
 		params:   params,
+		site:     site,
+		elided:   top.elided + 1,
 		modified: true,
 		last:     false,
 	}
@@ -72,9 +77,18 @@ func (s *callstack) pop() {
 	s.frames = s.frames[0 : len(s.frames)-1]
 }
 
+// snapshot returns a copy of the frames, outermost first.
+func (s *callstack) snapshot() []stackframe {
+	frames := make([]stackframe, len(s.frames))
+	copy(frames, s.frames)
+	return frames
+}
+
 type stackframe struct {
 	proc     *Proc
 	params   []Value
+	site     *ast.Pos // position of the call
+	elided   int      // number of tail calls replaced by this frame
 	modified bool
 	last     bool
 }
@@ -0,0 +1,84 @@
+package runtime
+
+import (
+	"bytes"
+	"math"
+)
+
+// eq reports whether two values are the same object. Numbers, characters and
+// strings are compared by value as they are not objects.
+func eq(a, b Value) bool {
+	return a == b
+}
+
+// eqv reports whether two values are the same: numbers of the same exactness
+// and value, characters, strings and symbols of the same content, and
+// otherwise the same object.
+func eqv(a, b Value) bool {
+	switch x := a.(type) {
+	case BigInt:
+		y, ok := b.(BigInt)
+		return ok && x.Cmp(y.Int) == 0
+	case Rat:
+		y, ok := b.(Rat)
+		return ok && x.Cmp(y.Rat) == 0
+	case Float:
+		y, ok := b.(Float)
+		if !ok {
+			return false
+		}
+		if math.IsNaN(float64(x)) {
+			return math.IsNaN(float64(y))
+		}
+		// 0.0 and -0.0 are not the same
+		return x == y && math.Signbit(float64(x)) == math.Signbit(float64(y))
+	case Symbol:
+		y, ok := b.(Symbol)
+		return ok && (x.string == y.string || *x.string == *y.string)
+	}
+	return a == b
+}
+
+// equal reports whether two values are equal in terms of inherent value:
+// pairs, vectors and bytevectors are compared item by item, and other values
+// with eqv.
+func equal(a, b Value) bool {
+	return equalIn(a, b, make(map[[2]Value]bool))
+}
+
+// equalIn compares pairs and vectors item by item. The comparisons in
+// progress are recorded in seen, and are taken as equal when they are met
+// again, so that comparing cyclic structures terminates.
+func equalIn(a, b Value, seen map[[2]Value]bool) bool {
+	switch x := a.(type) {
+	case *Pair:
+		y, ok := b.(*Pair)
+		if !ok {
+			return false
+		}
+		if x == y || seen[[2]Value{x, y}] {
+			return true
+		}
+		seen[[2]Value{x, y}] = true
+		return equalIn(x.first, y.first, seen) && equalIn(x.second, y.second, seen)
+	case *Vector:
+		y, ok := b.(*Vector)
+		if !ok || len(x.items) != len(y.items) {
+			return false
+		}
+		if x == y || seen[[2]Value{x, y}] {
+			return true
+		}
+		seen[[2]Value{x, y}] = true
+		for i := range x.items {
+			if !equalIn(x.items[i], y.items[i], seen) {
+				return false
+			}
+		}
+		return true
+	case *Bytevector:
+		y, ok := b.(*Bytevector)
+		return ok && bytes.Equal(x.bytes, y.bytes)
+	}
+	return eqv(a, b)
+}
@@ -0,0 +1,96 @@
+package runtime
+
+import (
+	"math"
+	"testing"
+
+	"github.com/dyzsr/mylisp/ast"
+)
+
+func Test_eqvEqual(t *testing.T) {
+	list := func(items ...Value) Value {
+		result, _ := _list(items...)
+		return result
+	}
+	shared := list(Int(1))
+	proc := &Proc{}
+	vec := &Vector{items: []Value{Int(1), String("a")}}
+
+	testData := []struct {
+		a, b       Value
+		eqv, equal bool
+	}{
+		{Int(1), Int(1), true, true},
+		{Int(1), Float(1), false, false},
+		{bigInt("100000000000000000000"), bigInt("100000000000000000000"), true, true},
+		{rat(1, 2), rat(2, 4), true, true},
+		{rat(1, 2), Float(0.5), false, false},
+		{Float(0), Float(math.Copysign(0, -1)), false, false},
+		{Float(math.NaN()), Float(math.NaN()), true, true},
+		{String("ab"), String("ab"), true, true},
+		{Char('a'), Char('a'), true, true},
+		{Symbol{symbolMap("a")}, Symbol{symbolMap("a")}, true, true},
+		{Symbol{symbolMap("a")}, Symbol{ast.SymbolMap("a")}, true, true},
+		{Nil{}, Nil{}, true, true},
+		{Bool(false), Nil{}, false, false},
+		{shared, shared, true, true},
+		{list(Int(1), list(Int(2))), list(Int(1), list(Int(2))), false, true},
+		{list(Int(1), Int(2)), list(Int(1)), false, false},
+		{vec, &Vector{items: []Value{Int(1), String("a")}}, false, true},
+		{vec, &Vector{items: []Value{Int(1)}}, false, false},
+		{&Bytevector{bytes: []byte{1}}, &Bytevector{bytes: []byte{1}}, false, true},
+		{proc, proc, true, true},
+		{proc, &Proc{}, false, false},
+		{builtinAdd, builtinAdd, true, true},
+		{newHashTable(false), newHashTable(false), false, false},
+	}
+	for _, test := range testData {
+		if result := eqv(test.a, test.b); result != test.eqv {
+			t.Errorf("(eqv? %s %s): expect %v, output %v", test.a, test.b, test.eqv, result)
+		}
+		if result := equal(test.a, test.b); result != test.equal {
+			t.Errorf("(equal? %s %s): expect %v, output %v", test.a, test.b, test.equal, result)
+		}
+	}
+}
+
+func Test_memberAssoc(t *testing.T) {
+	list := func(items ...Value) Value {
+		result, _ := _list(items...)
+		return result
+	}
+	key := list(Int(1))
+	members := list(Int(0), key, rat(1, 2))
+	entry := &Pair{first: key, second: Int(2)}
+	alist := list(&Pair{first: rat(1, 2), second: Int(1)}, entry)
+
+	testData := []struct {
+		op       *BuiltinProc
+		operands []Value
+		result   Value
+	}{
+		{builtinMemq, []Value{key, members}, members.(*Pair).second},
+		{builtinMemq, []Value{list(Int(1)), members}, Bool(false)},
+		{builtinMemv, []Value{rat(1, 2), members}, members.(*Pair).second.(*Pair).second},
+		{builtinMember, []Value{list(Int(1)), members}, members.(*Pair).second},
+		{builtinMember, []Value{Int(3), Nil{}}, Bool(false)},
+		{builtinAssq, []Value{key, alist}, entry},
+		{builtinAssv, []Value{rat(1, 2), alist}, alist.(*Pair).first},
+		{builtinAssoc, []Value{list(Int(1)), alist}, entry},
+		{builtinAssoc, []Value{Int(1), alist}, Bool(false)},
+	}
+	r := NewRuntime()
+	for _, test := range testData {
+		result, err := r.evalBuiltinProc(test.op, test.operands...)
+		if err != nil {
+			t.Errorf("\nerror: %s\ninput: {%s, %s}", err, test.op, test.operands)
+			continue
+		}
+		if result != test.result {
+			t.Errorf("\ninput: {%s, %s}\nexpect: {%s}\noutput: {%s}", test.op, test.operands, test.result, result)
+		}
+	}
+	if _, err := r.evalBuiltinProc(builtinAssoc, Int(1), list(Int(1))); err != errTypeMismatch {
+		t.Errorf("expect: %v, output: %v", errTypeMismatch, err)
+	}
+}
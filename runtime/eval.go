@@ -35,11 +35,33 @@ func (r *Runtime) eval(scope *ast.Scope, input ast.Expr) (Value, error) {
 		return nil, nil
 	}
 
+	value, err := r.evalExpr(scope, input)
+	if err != nil {
+		return nil, ast.WrapError(ast.RuntimePhase, input, err)
+	}
+	return value, nil
+}
+
+func (r *Runtime) evalExpr(scope *ast.Scope, input ast.Expr) (Value, error) {
 	switch expr := input.(type) {
 	case *ast.BoolLit:
 		return Bool(expr.Value), nil
 	case *ast.IntLit:
 		return Int(expr.Value), nil
+	case *ast.BigIntLit:
+		return BigInt{expr.Value}, nil
+	case *ast.RatLit:
+		return Rat{expr.Value}, nil
+	case *ast.FloatLit:
+		return Float(expr.Value), nil
+	case *ast.StringLit:
+		return String(expr.Value), nil
+	case *ast.CharLit:
+		return Char(expr.Value), nil
+	case *ast.VectorLit:
+		return r.evalQuote(scope, &ast.Quote{Expr: expr})
+	case *ast.BytevectorLit:
+		return &Bytevector{bytes: append([]byte{}, expr.Value...)}, nil
 	case *ast.Quote:
 		return r.evalQuote(scope, expr)
 	case *ast.Ident:
@@ -52,8 +74,24 @@ func (r *Runtime) eval(scope *ast.Scope, input ast.Expr) (Value, error) {
 		return r.evalSetExpr(scope, expr)
 	case *ast.LambdaExpr:
 		return r.evalLambdaExpr(scope, expr)
+	case *ast.CaseLambdaExpr:
+		return &Proc{
+			LambdaExpr: expr.List[0],
+			outer:      scope,
+			cases:      expr.List,
+		}, nil
 	case *ast.CondExpr:
 		return r.evalCondExpr(scope, expr)
+	case *ast.AndExpr:
+		return r.evalAndOr(scope, expr.List, false)
+	case *ast.OrExpr:
+		return r.evalAndOr(scope, expr.List, true)
+	case *ast.IfExpr:
+		return r.evalIfExpr(scope, expr)
+	case *ast.BeginExpr:
+		return r.evalBeginExpr(scope, expr)
+	case *ast.CaseExpr:
+		return r.evalCaseExpr(scope, expr)
 	}
 	return nil, errors.New("error: cannot eval input")
 }
@@ -64,6 +102,16 @@ func (r *Runtime) evalQuote(scope *ast.Scope, quote *ast.Quote) (Value, error) {
 		return Bool(expr.Value), nil
 	case *ast.IntLit:
 		return Int(expr.Value), nil
+	case *ast.BigIntLit:
+		return BigInt{expr.Value}, nil
+	case *ast.RatLit:
+		return Rat{expr.Value}, nil
+	case *ast.FloatLit:
+		return Float(expr.Value), nil
+	case *ast.StringLit:
+		return String(expr.Value), nil
+	case *ast.CharLit:
+		return Char(expr.Value), nil
 	case *ast.Ident:
 		return Symbol{symbolMap(*expr.Name)}, nil
 	case *ast.ListExpr:
@@ -76,6 +124,30 @@ func (r *Runtime) evalQuote(scope *ast.Scope, quote *ast.Quote) (Value, error) {
 			args = append(args, value)
 		}
 		return _list(args...)
+	case *ast.DottedList:
+		tail, err := r.evalQuote(scope, &ast.Quote{Expr: expr.Tail})
+		if err != nil {
+			return nil, err
+		}
+		for i := len(expr.List) - 1; i >= 0; i-- {
+			value, err := r.evalQuote(scope, &ast.Quote{Expr: expr.List[i]})
+			if err != nil {
+				return nil, err
+			}
+			tail = &Pair{first: value, second: tail}
+		}
+		return tail, nil
+	case *ast.VectorLit:
+		items := make([]Value, len(expr.List))
+		for i, expr := range expr.List {
+			var err error
+			if items[i], err = r.evalQuote(scope, &ast.Quote{Expr: expr}); err != nil {
+				return nil, err
+			}
+		}
+		return &Vector{items: items}, nil
+	case *ast.BytevectorLit:
+		return &Bytevector{bytes: append([]byte{}, expr.Value...)}, nil
 	}
 	return nil, errors.New("quote: bad value")
 }
@@ -93,13 +165,7 @@ func (r *Runtime) evalListExpr(scope *ast.Scope, listExpr *ast.ListExpr) (Value,
 		return nil, errors.New("missing procedure expression")
 	}
 
-	var tailcall bool
-	if r.enableTCOpt && !r.stack.empty() && r.stack.last() && r.lastInScope {
-		// mark tailcall when requirements are satisfied
-		tailcall = true
-		// turn off the flag since a list expression (procedure call) is not a scope
-		r.lastInScope = false
-	}
+	tailcall := r.tailcall()
 
 	valueList := make([]Value, len(listExpr.List))
 	for i, expr := range listExpr.List {
@@ -110,27 +176,51 @@ func (r *Runtime) evalListExpr(scope *ast.Scope, listExpr *ast.ListExpr) (Value,
 		}
 	}
 
-	operator := valueList[0]
-	operands := valueList[1:]
+	return r.call(valueList[0], valueList[1:], listExpr.Pos(), tailcall)
+}
 
+// tailcall reports whether a procedure call is in tail position, which
+// replaces the frame of the calling procedure.
+func (r *Runtime) tailcall() bool {
+	if r.enableTCOpt && !r.stack.empty() && r.stack.last() && r.lastInScope {
+		// turn off the flag since a list expression (procedure call) is not a scope
+		r.lastInScope = false
+		return true
+	}
+	return false
+}
+
+// call calls a procedure from site, or makes a tail call which is carried
+// out by evalProc of the calling procedure.
+func (r *Runtime) call(operator Value, operands []Value, site *ast.Pos, tailcall bool) (Value, error) {
 	switch op := operator.(type) {
 	case *BuiltinProc:
 		return r.evalBuiltinProc(op, operands...)
 	case *Proc:
 		if tailcall {
-			r.stack.modify(op, operands)
+			r.stack.modify(op, operands, site)
 			return nil, nil
 		}
-		r.stack.push(op, operands)
+		r.stack.push(op, operands, site)
 		result, err := r.evalProc(op, operands...)
 		r.stack.pop()
 		return result, err
 	}
-	return nil, errors.New("not a procedure")
+	return nil, errNotProcedure
+}
+
+// evalNonTail evaluates an expression which is not in tail position even if
+// the enclosing expression is, such as a condition.
+func (r *Runtime) evalNonTail(scope *ast.Scope, input ast.Expr) (Value, error) {
+	tail := r.lastInScope
+	r.lastInScope = false
+	value, err := r.eval(scope, input)
+	r.lastInScope = tail
+	return value, err
 }
 
 func (r *Runtime) evalDefineExpr(scope *ast.Scope, defineExpr *ast.DefineExpr) (Value, error) {
-	value, err := r.eval(scope, defineExpr.Value)
+	value, err := r.evalNonTail(scope, defineExpr.Value)
 	if err != nil {
 		return nil, err
 	}
@@ -142,7 +232,7 @@ func (r *Runtime) evalDefineExpr(scope *ast.Scope, defineExpr *ast.DefineExpr) (
 }
 
 func (r *Runtime) evalSetExpr(scope *ast.Scope, setExpr *ast.SetExpr) (Value, error) {
-	value, err := r.eval(scope, setExpr.Value)
+	value, err := r.evalNonTail(scope, setExpr.Value)
 	if err != nil {
 		return nil, err
 	}
@@ -165,15 +255,11 @@ func (r *Runtime) evalLambdaExpr(scope *ast.Scope, lambdaExpr *ast.LambdaExpr) (
 func (r *Runtime) evalCondExpr(scope *ast.Scope, condExpr *ast.CondExpr) (Value, error) {
 	for _, branch := range condExpr.List {
 		if !branch.Else {
-			condValue, err := r.eval(scope, branch.Condition)
+			condValue, err := r.evalNonTail(scope, branch.Condition)
 			if err != nil {
 				return nil, err
 			}
-			boolean, ok := condValue.(Bool)
-			if !ok {
-				return nil, errors.New("cond: error condition type")
-			}
-			if !boolean {
+			if !isTrue(condValue) {
 				continue
 			}
 		}
@@ -184,10 +270,93 @@ func (r *Runtime) evalCondExpr(scope *ast.Scope, condExpr *ast.CondExpr) (Value,
 	return Nil{}, nil
 }
 
+// evalAndOr evaluates the expressions of and, or of or, until one of them
+// decides the result, which is false for and, or true for or. The last
+// expression is in tail position.
+func (r *Runtime) evalAndOr(scope *ast.Scope, list []ast.Expr, or bool) (Value, error) {
+	if len(list) == 0 {
+		return Bool(!or), nil
+	}
+	for _, expr := range list[:len(list)-1] {
+		value, err := r.evalNonTail(scope, expr)
+		if err != nil {
+			return nil, err
+		}
+		if isTrue(value) == or {
+			return value, nil
+		}
+	}
+	return r.eval(scope, list[len(list)-1])
+}
+
+func (r *Runtime) evalIfExpr(scope *ast.Scope, ifExpr *ast.IfExpr) (Value, error) {
+	condValue, err := r.evalNonTail(scope, ifExpr.Condition)
+	if err != nil {
+		return nil, err
+	}
+	branch := ifExpr.Then
+	if !isTrue(condValue) {
+		branch = ifExpr.Else
+	}
+	if branch == nil {
+		return Nil{}, nil
+	}
+	return r.eval(scope, branch)
+}
+
+func (r *Runtime) evalBeginExpr(scope *ast.Scope, beginExpr *ast.BeginExpr) (Value, error) {
+	if len(beginExpr.Body) == 0 {
+		return Nil{}, nil
+	}
+	return r.evalScope(scope, false, beginExpr.Body)
+}
+
+func (r *Runtime) evalCaseExpr(scope *ast.Scope, caseExpr *ast.CaseExpr) (Value, error) {
+	key, err := r.evalNonTail(scope, caseExpr.Key)
+	if err != nil {
+		return nil, err
+	}
+	for _, clause := range caseExpr.List {
+		match := clause.Else
+		for _, expr := range clause.Data {
+			datum, err := r.evalQuote(scope, &ast.Quote{Expr: expr})
+			if err != nil {
+				return nil, err
+			}
+			if eqv(key, datum) {
+				match = true
+				break
+			}
+		}
+		if !match {
+			continue
+		}
+
+		if clause.Arrow {
+			proc, err := r.evalNonTail(scope, clause.Body[0])
+			if err != nil {
+				return nil, err
+			}
+			return r.call(proc, []Value{key}, clause.Pos(), r.tailcall())
+		}
+		inner := ast.NewScope(scope)
+		return r.evalScope(inner, false, clause.Body)
+	}
+	return Nil{}, nil
+}
+
 func (r *Runtime) evalBuiltinProc(op *BuiltinProc, operands ...Value) (value Value, err error) {
+	if op.call != nil {
+		return op.call(r, operands...)
+	}
 	return op.proc(operands...)
 }
 
+// apply calls a procedure on behalf of a built-in.
+func (r *Runtime) apply(operator Value, operands ...Value) (Value, error) {
+	return r.call(operator, operands, nil, false)
+}
+
 func (r *Runtime) evalProc(proc *Proc, operands ...Value) (Value, error) {
 	// println("callstack depth:", len(r.stack.frames))
 	for {
@@ -197,23 +366,73 @@ func (r *Runtime) evalProc(proc *Proc, operands ...Value) (Value, error) {
 			r.stack.unmodify()
 		}
 
-		if len(proc.Args) != len(operands) {
-			return nil, errArityMismatch
+		lambda := proc.match(len(operands))
+		if lambda == nil {
+			return nil, r.traceback(errArityMismatch)
 		}
-		scope := ast.NewScope(proc.outer)
-		for i, arg := range proc.Args {
-			scope.Insert(arg.Name, operands[i])
+		scope, err := r.bind(proc.outer, lambda, operands)
+		if err != nil {
+			return nil, r.traceback(err)
 		}
 
-		result, err := r.evalScope(scope, true, proc.Body)
+		result, err := r.evalScope(scope, true, lambda.Body)
+		if err != nil {
+			return nil, r.traceback(err)
+		}
 		if r.stack.modified() {
 			continue
 		}
-		return result, err
+		return result, nil
+	}
+}
+
+// bind makes the scope of a call, binding the parameters of lambda to the
+// operands, whose number it accepts.
+func (r *Runtime) bind(outer *ast.Scope, lambda *ast.LambdaExpr, operands []Value) (*ast.Scope, error) {
+	scope := ast.NewScope(outer)
+	for i, arg := range lambda.Args {
+		scope.Insert(arg.Name, operands[i])
+	}
+	operands = operands[len(lambda.Args):]
+
+	for _, opt := range lambda.Optional {
+		var value Value = Bool(false)
+		switch {
+		case len(operands) > 0:
+			value, operands = operands[0], operands[1:]
+		case opt.Default != nil:
+			var err error
+			if value, err = r.evalNonTail(scope, opt.Default); err != nil {
+				return nil, err
+			}
+		}
+		scope.Insert(opt.Ident.Name, value)
+	}
+
+	if lambda.Rest != nil {
+		rest, err := _list(operands...)
+		if err != nil {
+			return nil, err
+		}
+		scope.Insert(lambda.Rest.Name, rest)
+	}
+	return scope, nil
+}
+
+// traceback attaches the current callstack to err, unless a deeper procedure
+// call has already done so.
+func (r *Runtime) traceback(err error) error {
+	var e *TraceError
+	if errors.As(err, &e) {
+		return err
 	}
+	return &TraceError{Err: err, frames: r.stack.snapshot()}
 }
 
 func (r *Runtime) evalScope(scope *ast.Scope, isProc bool, list []ast.Expr) (Value, error) {
+	// the last expression of a nested scope is in tail position only if the
+	// scope itself is
+	tail := isProc || r.lastInScope
 	// turn off the flag when entering a new scope
 	r.lastInScope = false
 
@@ -221,7 +440,7 @@ func (r *Runtime) evalScope(scope *ast.Scope, isProc bool, list []ast.Expr) (Val
 	for i, expr := range list {
 		if i == len(list)-1 {
 			// turn on the flag if it is last in the scope
-			r.lastInScope = true
+			r.lastInScope = tail
 			if isProc {
 				r.stack.setLast(true)
 			}
@@ -1,6 +1,7 @@
 package runtime
 
 import (
+	"errors"
 	"reflect"
 	"strings"
 	"testing"
@@ -53,6 +54,14 @@ func Test_Eval(t *testing.T) {
 	t.Run("LambdaExpr", makeTest(testLambdaExpr))
 	t.Run("CondExpr", makeTest(testCondExpr))
 	t.Run("QuoteExpr", makeTest(testQuote))
+	t.Run("Vector", makeTest(testVector))
+	t.Run("List", makeTest(testList))
+	t.Run("Let", makeTest(testLet))
+	t.Run("IfBeginCase", makeTest(testIfBeginCase))
+	t.Run("AndOr", makeTest(testAndOr))
+	t.Run("Params", makeTest(testParams))
+	t.Run("Quasiquote", makeTest(testQuasiquote))
+	t.Run("Macro", makeTest(testMacro))
 	t.Run("TailCall", makeTest(testTailCall))
 	t.Run("ChurchNumeral", makeTest(testChurchNumeral))
 	t.Run("MessagePassing", makeTest(testMessagePassing))
@@ -248,6 +257,281 @@ var (
 				},
 			},
 		},
+		{str: "'(1 . 2)", result: &Pair{first: Int(1), second: Int(2)}},
+		{
+			str: "'(a (b) . c)",
+			result: &Pair{
+				first: Symbol{symbolMap("a")},
+				second: &Pair{
+					first:  &Pair{first: Symbol{symbolMap("b")}, second: Nil{}},
+					second: Symbol{symbolMap("c")},
+				},
+			},
+		},
+		{str: "'(1 . (2 . ()))", result: &Pair{first: Int(1), second: &Pair{first: Int(2), second: Nil{}}}},
+		{str: "(equal? (cons 1 2) '(1 . 2))", result: Bool(true)},
+	}
+
+	testQuasiquote = []testStruct{
+		{str: "(define x 2)", result: Nil{}},
+		{str: "(define xs (list 3 4))", result: Nil{}},
+		{str: "`(1 ,x)", result: listOf(Int(1), Int(2))},
+		{str: "`(1 ,@xs 5)", result: listOf(Int(1), Int(3), Int(4), Int(5))},
+		{str: "`(,@xs ,@xs)", result: listOf(Int(3), Int(4), Int(3), Int(4))},
+		{str: "`(1 . ,x)", result: &Pair{first: Int(1), second: Int(2)}},
+		{str: "`(0 ,@xs . ,x)", result: &Pair{first: Int(0), second: &Pair{first: Int(3), second: &Pair{first: Int(4), second: Int(2)}}}},
+		{str: "`#(1 ,x ,@xs)", result: &Vector{items: []Value{Int(1), Int(2), Int(3), Int(4)}}},
+		{str: "`,(if (> x 1) 'big 'small)", result: Symbol{symbolMap("big")}},
+		{str: "(equal? `(a (b ,(* x 3)) c) '(a (b 6) c))", result: Bool(true)},
+		{str: "(equal? `(a `(b ,(c ,x))) '(a `(b ,(c 2))))", result: Bool(true)},
+		{str: "(equal? `(a `(b ,,@xs)) '(a `(b (unquote 3 4))))", result: Bool(true)},
+		{str: "(define (f list) `(a ,@list b))", result: Nil{}},
+		{str: "(f (cons 1 (cons 2 nil)))", result: listOf(Symbol{symbolMap("a")}, Int(1), Int(2), Symbol{symbolMap("b")})},
+		{str: "(define (h cons) `(1 ,cons . 2))", result: Nil{}},
+		{str: "(h 0)", result: &Pair{first: Int(1), second: &Pair{first: Int(0), second: Int(2)}}},
+		{str: "(let ((append 0) (list->vector 0)) `#(,@xs))", result: &Vector{items: []Value{Int(3), Int(4)}}},
+		{str: "(let ((name 'f)) `(define (,name . args) args))", result: listOf(
+			Symbol{symbolMap("define")},
+			&Pair{first: Symbol{symbolMap("f")}, second: Symbol{symbolMap("args")}},
+			Symbol{symbolMap("args")},
+		)},
+	}
+
+	testMacro = []testStruct{
+		{
+			str: `
+(define-syntax swap!
+  (syntax-rules ()
+    ((_ a b) (let ((tmp a)) (set! a b) (set! b tmp)))))`,
+			result: Nil{},
+		},
+		{str: "(define tmp 1)", result: Nil{}},
+		{str: "(define other 2)", result: Nil{}},
+		{str: "(swap! tmp other)", result: Nil{}},
+		{str: "(list tmp other)", result: listOf(Int(2), Int(1))},
+		{
+			str: `
+(define-syntax my-or
+  (syntax-rules ()
+    ((_) false)
+    ((_ e) e)
+    ((_ e r ...) (let ((t e)) (if t t (my-or r ...))))))`,
+			result: Nil{},
+		},
+		{str: "(let ((t 5)) (my-or false t))", result: Int(5)},
+		{str: "(my-or)", result: Bool(false)},
+		{
+			str: `
+(define-syntax my-cond
+  (syntax-rules (else)
+    ((_ (else e ...)) (begin e ...))
+    ((_ (c e ...) clause ...) (if c (begin e ...) (my-cond clause ...)))))`,
+			result: Nil{},
+		},
+		{str: "(my-cond (false 1) ((= 1 2) 2) (else 3))", result: Int(3)},
+		{
+			str: `
+(define-syntax my-let*
+  (syntax-rules ()
+    ((_ () body ...) (let () body ...))
+    ((_ ((x v) rest ...) body ...) (let ((x v)) (my-let* (rest ...) body ...)))))`,
+			result: Nil{},
+		},
+		{str: "(my-let* ((a 1) (b (+ a 1))) (* a b))", result: Int(2)},
+		{
+			str: `
+(define-syntax flat
+  (syntax-rules ()
+    ((_ (a b ...) ...) '(a ... b ... ...))))`,
+			result: Nil{},
+		},
+		{str: "(flat (1 2 3) (4) (5 6))", result: listOf(Int(1), Int(4), Int(5), Int(2), Int(3), Int(6))},
+		{
+			str: `
+(define-syntax tail
+  (syntax-rules ()
+    ((_ a ... z) 'z)
+    ((_ . rest) 'rest)))`,
+			result: Nil{},
+		},
+		{str: "(tail 1 2 3)", result: Int(3)},
+		{str: "(tail)", result: Nil{}},
+		{str: "(let-syntax ((one (syntax-rules () ((_) 1)))) (+ (one) (one)))", result: Int(2)},
+		{
+			str: `
+(letrec-syntax
+    ((ev? (syntax-rules () ((_) true) ((_ x . r) (od? . r))))
+     (od? (syntax-rules () ((_) false) ((_ x . r) (ev? . r)))))
+  (ev? 1 2 3 4))`,
+			result: Bool(true),
+		},
+		{
+			str: `
+(define (f x)
+  (define-syntax twice (syntax-rules () ((_ e) (* 2 e))))
+  (twice x))`,
+			result: Nil{},
+		},
+		{str: "(f 21)", result: Int(42)},
+		{
+			str: `
+(define-syntax define-getter
+  (syntax-rules ()
+    ((_ name v) (define (name) v))))`,
+			result: Nil{},
+		},
+		{str: "(define-getter answer 42)", result: Nil{}},
+		{str: "(define x 10)", result: Nil{}},
+		{str: "(define-syntax m (syntax-rules () ((_) (list x (let ((x 1)) x)))))", result: Nil{}},
+		{str: "(m)", result: listOf(Int(10), Int(1))},
+		{str: "(answer)", result: Int(42)},
+	}
+
+	testVector = []testStruct{
+		{str: "#(1 (a b) #())", result: &Vector{items: []Value{
+			Int(1),
+			&Pair{first: Symbol{symbolMap("a")}, second: &Pair{first: Symbol{symbolMap("b")}, second: Nil{}}},
+			&Vector{items: []Value{}},
+		}}},
+		{str: "'#(x 'y)", result: &Vector{items: []Value{
+			Symbol{symbolMap("x")},
+			&Pair{first: Symbol{symbolMap("quote")}, second: &Pair{first: Symbol{symbolMap("y")}, second: Nil{}}},
+		}}},
+		{str: "(define grid (make-vector 3 0))", result: Nil{}},
+		{
+			str: `
+				(define fill
+				 (lambda (i)
+				  (cond ((< i 3) (vector-set! grid i (* i i)) (fill (+ i 1))))))
+			`,
+			result: Nil{},
+		},
+		{str: "(fill 0)", result: Nil{}},
+		{str: "grid", result: &Vector{items: []Value{Int(0), Int(1), Int(4)}}},
+		{str: "(vector-map (lambda (x y) (+ x y)) grid #(10 20 30 40))", result: &Vector{items: []Value{Int(10), Int(21), Int(34)}}},
+		{str: "(define total 0)", result: Nil{}},
+		{str: "(vector-for-each (lambda (x) (set! total (+ total x))) grid)", result: Nil{}},
+		{str: "total", result: Int(5)},
+	}
+
+	testList = []testStruct{
+		{
+			str: `
+				(define count
+				 (lambda (n acc)
+				  (cond ((= n 0) acc)
+				        (else (count (- n 1) (+ acc 1))))))
+			`,
+			result: Nil{},
+		},
+		{str: "(map (lambda (n) (count n 0)) '(100000 3))", result: &Pair{first: Int(100000), second: &Pair{first: Int(3), second: Nil{}}}},
+		{str: "(define total 0)", result: Nil{}},
+		{str: "(for-each (lambda (x y) (set! total (+ total (* x y)))) '(1 2 3) '(4 5 6))", result: Nil{}},
+		{str: "total", result: Int(32)},
+		{str: "(fold-left (lambda (acc x) (+ (* acc 10) x)) 0 (filter (lambda (x) (< x 5)) (iota 8)))", result: Int(1234)},
+		{str: "(apply (lambda (a b c) (- a b c)) 10 '(2 3))", result: Int(5)},
+		{
+			str: "(map (lambda (p) (car (cdr p))) (sort '((1 a) (0 b) (1 c) (0 d)) (lambda (x y) (< (car x) (car y)))))",
+			result: &Pair{first: Symbol{symbolMap("b")}, second: &Pair{first: Symbol{symbolMap("d")},
+				second: &Pair{first: Symbol{symbolMap("a")}, second: &Pair{first: Symbol{symbolMap("c")}, second: Nil{}}}}},
+		},
+	}
+
+	testLet = []testStruct{
+		{str: "(define x 10)", result: Nil{}},
+		{str: "(let ((x 1) (y x)) (+ x y))", result: Int(11)},
+		{str: "(let* ((x 1) (y x)) (+ x y))", result: Int(2)},
+		{str: "(let () (define z 3) z)", result: Int(3)},
+		{
+			str: `
+				(letrec ((even? (lambda (n) (cond ((= n 0) true) (else (odd? (- n 1))))))
+				         (odd? (lambda (n) (cond ((= n 0) false) (else (even? (- n 1)))))))
+				 (even? 100001))
+			`,
+			result: Bool(false),
+		},
+		{str: "(letrec* ((a 1) (b (+ a 1))) (* a b))", result: Int(2)},
+		{
+			str: `
+				(let loop ((i 0) (acc 0))
+				 (cond ((= i 100000) acc)
+				       (else (loop (+ i 1) (+ acc i)))))
+			`,
+			result: Int(4999950000),
+		},
+		{str: "(define loop 3)", result: Nil{}},
+		{str: "(let loop ((i loop) (n 0)) (cond ((= i 0) n) (else (loop (- i 1) (+ n 1)))))", result: Int(3)},
+		{str: "loop", result: Int(3)},
+	}
+
+	testIfBeginCase = []testStruct{
+		{str: "(if true 1 2)", result: Int(1)},
+		{str: "(if false 1)", result: Nil{}},
+		{str: "(if nil 'yes 'no)", result: Symbol{symbolMap("yes")}},
+		{str: "(when (> 2 1) 'a 'b)", result: Symbol{symbolMap("b")}},
+		{str: "(unless (> 2 1) 'a 'b)", result: Nil{}},
+		{str: "(begin (define x 1) (define y 2))", result: Nil{}},
+		{str: "(+ x y)", result: Int(3)},
+		{str: "(case (* 2 3) ((2 3 5 7) 'prime) ((1 4 6 8 9) 'composite))", result: Symbol{symbolMap("composite")}},
+		{str: "(case 'x ((a) 1) (else => (lambda (v) (list v))))", result: &Pair{first: Symbol{symbolMap("x")}, second: Nil{}}},
+		{str: "(case 5 ((5) => (lambda (v) (* v v))) (else 0))", result: Int(25)},
+		{str: "(case 10 ((1) 1))", result: Nil{}},
+		{str: "(define loop (lambda (n) (if (= n 0) 'done (loop (- n 1)))))", result: Nil{}},
+		{str: "(loop 100000)", result: Symbol{symbolMap("done")}},
+		{str: "(define loop (lambda (n) (case n ((0) 'done) (else => (lambda (m) (loop (- m 1)))))))", result: Nil{}},
+		{str: "(loop 100000)", result: Symbol{symbolMap("done")}},
+		{str: "(define loop (lambda (n) (unless (= n 0) (begin (loop (- n 1))))))", result: Nil{}},
+		{str: "(loop 100000)", result: Nil{}},
+		// calls out of tail position inside an expression in tail position
+		{str: "(define id (lambda (x) x))", result: Nil{}},
+		{str: "(define f (lambda (x) (cond ((id x) 'yes) (else 'no))))", result: Nil{}},
+		{str: "(f false)", result: Symbol{symbolMap("no")}},
+		{str: "(define g (lambda (x) (+ 1 (cond (true (id x))))))", result: Nil{}},
+		{str: "(g 1)", result: Int(2)},
+		{str: "(define h (lambda (x) (if (id x) (+ 1 (begin (id x))) 0)))", result: Nil{}},
+		{str: "(h 1)", result: Int(2)},
+	}
+
+	testAndOr = []testStruct{
+		{str: "(define x nil)", result: Nil{}},
+		{str: "(and (pair? x) (car x))", result: Bool(false)},
+		{str: "(and 1 2 3)", result: Int(3)},
+		{str: "(and)", result: Bool(true)},
+		{str: "(or)", result: Bool(false)},
+		{str: "(or false 2 (car 1))", result: Int(2)},
+		{str: "(or false false)", result: Bool(false)},
+		{str: "(cond (1 'one))", result: Symbol{symbolMap("one")}},
+		{str: "(cond (nil 'yes))", result: Symbol{symbolMap("yes")}},
+		{str: "(define loop (lambda (n) (or (= n 0) (loop (- n 1)))))", result: Nil{}},
+		{str: "(loop 100000)", result: Bool(true)},
+		{str: "(define loop (lambda (n) (and (> n 0) (loop (- n 1)))))", result: Nil{}},
+		{str: "(loop 100000)", result: Bool(false)},
+		{str: "(define id (lambda (x) x))", result: Nil{}},
+		{str: "(define f (lambda (x) (and (id x) 'yes)))", result: Nil{}},
+		{str: "(f false)", result: Bool(false)},
+		{str: "(f 1)", result: Symbol{symbolMap("yes")}},
+	}
+
+	testParams = []testStruct{
+		{str: "(define (square x) (* x x))", result: Nil{}},
+		{str: "(square 5)", result: Int(25)},
+		{str: "(define ((adder n) x) (+ n x))", result: Nil{}},
+		{str: "((adder 3) 4)", result: Int(7)},
+		{str: "(define (f x . rest) rest)", result: Nil{}},
+		{str: "(f 1)", result: Nil{}},
+		{str: "(f 1 2 3)", result: &Pair{first: Int(2), second: &Pair{first: Int(3), second: Nil{}}}},
+		{str: "((lambda args args) 1)", result: &Pair{first: Int(1), second: Nil{}}},
+		{str: "(define (g a #!optional (b (* a 2)) c) (list b c))", result: Nil{}},
+		{str: "(g 1)", result: &Pair{first: Int(2), second: &Pair{first: Bool(false), second: Nil{}}}},
+		{str: "(g 1 5 6)", result: &Pair{first: Int(5), second: &Pair{first: Int(6), second: Nil{}}}},
+		{str: "(define area (case-lambda ((r) (* 3 r r)) ((w h) (* w h)) ((a b . more) more)))", result: Nil{}},
+		{str: "(area 2)", result: Int(12)},
+		{str: "(area 2 3)", result: Int(6)},
+		{str: "(area 1 2 3)", result: &Pair{first: Int(3), second: Nil{}}},
+		{str: "(procedure-arity area)", result: &Pair{first: Int(1), second: Bool(false)}},
+		{str: "(procedure-arity g)", result: &Pair{first: Int(1), second: Int(3)}},
+		{str: "(define (loop n . acc) (if (= n 0) acc (loop (- n 1) n)))", result: Nil{}},
+		{str: "(loop 100000)", result: &Pair{first: Int(1), second: Nil{}}},
 	}
 
 	testTailCall = []testStruct{
@@ -265,6 +549,7 @@ var (
 		},
 		{str: "(fac 5)", result: Int(120)},
 		{str: "(fac 15)", result: Int(1307674368000)},
+		{str: "(fac 30)", result: bigInt("265252859812191058636308480000000")},
 
 		{
 			str: `
@@ -335,3 +620,107 @@ var (
 		{str: "(fib 20)", result: Int(10946)},
 	}
 )
+
+func Test_EvalError(t *testing.T) {
+	testData := []struct {
+		str   string
+		phase ast.Phase
+		pos   ast.Pos
+	}{
+		{str: "(car 1)", phase: ast.RuntimePhase, pos: ast.NewPos(1, 1)},
+		{str: "(+ 1\n   (car (cons undefined 2)))", phase: ast.RuntimePhase, pos: ast.NewPos(2, 15)},
+		{str: "(define f (lambda (x)\n  (car x)))\n(f 1)", phase: ast.RuntimePhase, pos: ast.NewPos(2, 3)},
+		{str: "(lambda x)", phase: ast.CompilePhase, pos: ast.NewPos(1, 1)},
+		{str: "(+ 1\n  (a . b))", phase: ast.CompilePhase, pos: ast.NewPos(2, 3)},
+		{str: "`(1\n  ,(car 1))", phase: ast.RuntimePhase, pos: ast.NewPos(2, 4)},
+		{str: "`(1 ,@2 3)", phase: ast.RuntimePhase, pos: ast.NewPos(1, 2)},
+		{str: "(list\n  ,x)", phase: ast.CompilePhase, pos: ast.NewPos(2, 3)},
+		{str: "`(1 . ,@x)", phase: ast.CompilePhase, pos: ast.NewPos(1, 7)},
+		{str: "(define-syntax m (syntax-rules () ((_ x) (car x))))\n(+ 1\n  (m 1))", phase: ast.RuntimePhase, pos: ast.NewPos(3, 3)},
+		{str: "(define-syntax m (syntax-rules () ((_ x) x)))\n(m)", phase: ast.CompilePhase, pos: ast.NewPos(2, 1)},
+		{str: "(define-syntax m (syntax-rules () ((_ x ... y ...) x)))", phase: ast.CompilePhase, pos: ast.NewPos(1, 1)},
+		{str: "(define-syntax m (syntax-rules () ((_) (m))))\n(+ 1\n  (m))", phase: ast.CompilePhase, pos: ast.NewPos(3, 3)},
+		{str: "(define-syntax m (syntax-rules () ((_ x) (lambda () (m (x))))))\n(m 1)", phase: ast.CompilePhase, pos: ast.NewPos(2, 1)},
+		{str: "(define (f x #!optional y) x)\n(f 1 2 3)", phase: ast.RuntimePhase, pos: ast.NewPos(2, 1)},
+		{str: "(define (f #!optional (x (car 1))) x)\n(f)", phase: ast.RuntimePhase, pos: ast.NewPos(1, 26)},
+	}
+
+	for _, test := range testData {
+		ct := compiletime.NewCompileTime()
+		rt := NewRuntime()
+		p := parser.NewParser(token.NewLexer(strings.NewReader(test.str)))
+
+		var err error
+		for err == nil {
+			expr, ok := p.Next()
+			if !ok {
+				break
+			}
+			if expr, err = ct.Eval(expr); err == nil {
+				_, err = rt.Eval(expr)
+			}
+		}
+
+		var e *ast.Error
+		if !errors.As(err, &e) {
+			t.Errorf("\ninput: '%s'\nexpect a located error\noutput: %v", test.str, err)
+			continue
+		}
+		if e.Phase != test.phase || *e.From != test.pos {
+			t.Errorf("\ninput: '%s'\nexpect: %s %s\noutput: %s %s", test.str, test.phase, test.pos, e.Phase, e.From)
+		}
+		if !rt.stack.empty() {
+			t.Error("callstack is not empty")
+		}
+	}
+}
+
+func Test_EvalTraceback(t *testing.T) {
+	str := `
+		(define g (lambda (x) (car x)))
+		(define f (lambda (n)
+		 (cond ((= n 0) (g n))
+		       (else (f (- n 1))))))
+		(define h (lambda (n) (+ 1 (f n))))
+		(h 3)`
+	expect := []string{
+		"7:3: (h 3)",
+		"... 4 frames elided by tail calls",
+		"4:19: (g 0)",
+	}
+
+	ct := compiletime.NewCompileTime()
+	rt := NewRuntime()
+	p := parser.NewParser(token.NewLexer(strings.NewReader(str)))
+
+	var err error
+	for err == nil {
+		expr, ok := p.Next()
+		if !ok {
+			break
+		}
+		if expr, err = ct.Eval(expr); err == nil {
+			_, err = rt.Eval(expr)
+		}
+	}
+
+	var e *TraceError
+	if !errors.As(err, &e) {
+		t.Fatalf("expect a traceback, output: %v", err)
+	}
+	if !errors.Is(err, errTypeMismatch) {
+		t.Errorf("expect: %v, output: %v", errTypeMismatch, err)
+	}
+	if result := e.Traceback(); !reflect.DeepEqual(result, expect) {
+		t.Errorf("\nexpect: %q\noutput: %q", expect, result)
+	}
+	if !rt.stack.empty() {
+		t.Error("callstack is not empty")
+	}
+}
+
+// listOf makes the proper list of items.
+func listOf(items ...Value) Value {
+	result, _ := _list(items...)
+	return result
+}
@@ -0,0 +1,166 @@
+package runtime
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"math"
+)
+
+type hashEntry struct {
+	key     Value
+	value   Value
+	deleted bool
+}
+
+func newHashTable(eqv bool) *HashTable {
+	return &HashTable{
+		eqv:     eqv,
+		buckets: make(map[interface{}][]int),
+	}
+}
+
+// bucket returns the key of the bucket holding key. An eqv? table keeps the
+// keys which are not eqv? in different buckets, and an equal? table groups the
+// keys by their hashes.
+func (t *HashTable) bucket(key Value) interface{} {
+	if t.eqv {
+		switch key := key.(type) {
+		case BigInt:
+			return key.String()
+		case Rat:
+			return key.String()
+		case Float:
+			return math.Float64bits(float64(key))
+		case Symbol:
+			return *key.string
+		}
+		return key
+	}
+	h := fnv.New64a()
+	hashValue(h, key, hashDepth)
+	return h.Sum64()
+}
+
+func (t *HashTable) find(key Value) (interface{}, int, bool) {
+	bucket := t.bucket(key)
+	for _, i := range t.buckets[bucket] {
+		if t.eqv && eqv(t.entries[i].key, key) || !t.eqv && equal(t.entries[i].key, key) {
+			return bucket, i, true
+		}
+	}
+	return bucket, 0, false
+}
+
+func (t *HashTable) get(key Value) (Value, bool) {
+	if _, i, ok := t.find(key); ok {
+		return t.entries[i].value, true
+	}
+	return nil, false
+}
+
+func (t *HashTable) set(key, value Value) {
+	bucket, i, ok := t.find(key)
+	if ok {
+		t.entries[i].value = value
+		return
+	}
+	t.buckets[bucket] = append(t.buckets[bucket], len(t.entries))
+	t.entries = append(t.entries, hashEntry{key: key, value: value})
+	t.count++
+}
+
+func (t *HashTable) delete(key Value) {
+	bucket, i, ok := t.find(key)
+	if !ok {
+		return
+	}
+	indexes := t.buckets[bucket]
+	for j := range indexes {
+		if indexes[j] == i {
+			indexes = append(indexes[:j], indexes[j+1:]...)
+			break
+		}
+	}
+	if len(indexes) == 0 {
+		delete(t.buckets, bucket)
+	} else {
+		t.buckets[bucket] = indexes
+	}
+	t.entries[i] = hashEntry{deleted: true}
+	t.count--
+
+	// drop the deleted entries once they make up half of the table
+	if t.count < len(t.entries)/2 {
+		t.rehash()
+	}
+}
+
+func (t *HashTable) rehash() {
+	entries := t.entries
+	t.buckets = make(map[interface{}][]int)
+	t.entries = nil
+	t.count = 0
+	for _, entry := range entries {
+		if !entry.deleted {
+			t.set(entry.key, entry.value)
+		}
+	}
+}
+
+// each calls f on the entries in insertion order.
+func (t *HashTable) each(f func(key, value Value)) {
+	for _, entry := range t.entries {
+		if !entry.deleted {
+			f(entry.key, entry.value)
+		}
+	}
+}
+
+// hashDepth limits how deep hashValue looks into pairs and vectors.
+const hashDepth = 4
+
+// hashValue writes a hash of v to h, so that values which are equal? have the
+// same hash.
+func hashValue(h hash.Hash64, v Value, depth int) {
+	var buf [8]byte
+	writeUint := func(n uint64) {
+		binary.LittleEndian.PutUint64(buf[:], n)
+		h.Write(buf[:])
+	}
+
+	writeUint(uint64(v.Type().Kind()))
+	if depth == 0 {
+		return
+	}
+	switch v := v.(type) {
+	case Bool:
+		if v {
+			writeUint(1)
+		}
+	case Int:
+		writeUint(uint64(v))
+	case BigInt:
+		h.Write(v.Bytes())
+	case Rat:
+		h.Write([]byte(v.String()))
+	case Float:
+		writeUint(math.Float64bits(float64(v)))
+	case String:
+		h.Write([]byte(v))
+	case Char:
+		writeUint(uint64(v))
+	case Symbol:
+		h.Write([]byte(*v.string))
+	case *Bytevector:
+		h.Write(v.bytes)
+	case *Pair:
+		hashValue(h, v.first, depth-1)
+		hashValue(h, v.second, depth-1)
+	case *Vector:
+		writeUint(uint64(len(v.items)))
+		for i := 0; i < len(v.items) && i < hashDepth; i++ {
+			hashValue(h, v.items[i], depth-1)
+		}
+	}
+}
@@ -0,0 +1,354 @@
+package runtime
+
+import (
+	"errors"
+	"math"
+	"math/big"
+)
+
+// level is a level of the numeric tower. A number can be converted to any
+// higher level, and an operation on numbers of different levels is carried
+// out at the highest of them.
+type level int
+
+const (
+	intLevel level = iota
+	bigLevel
+	ratLevel
+	floatLevel
+)
+
+// errIntOverflow tells that an operation on Ints has to be carried out on
+// BigInts instead.
+var errIntOverflow = errors.New("integer overflow")
+
+func numberLevel(v Value) (level, bool) {
+	switch v.(type) {
+	case Int:
+		return intLevel, true
+	case BigInt:
+		return bigLevel, true
+	case Rat:
+		return ratLevel, true
+	case Float:
+		return floatLevel, true
+	}
+	return 0, false
+}
+
+func isNumber(v Value) bool {
+	_, ok := numberLevel(v)
+	return ok
+}
+
+func isExactInteger(v Value) bool {
+	l, ok := numberLevel(v)
+	return ok && l <= bigLevel
+}
+
+func isExact(v Value) bool {
+	l, ok := numberLevel(v)
+	return ok && l <= ratLevel
+}
+
+// checkNumbers checks that all the arguments are numbers.
+func checkNumbers(args []Value) error {
+	for _, arg := range args {
+		if !isNumber(arg) {
+			return errTypeMismatch
+		}
+	}
+	return nil
+}
+
+// normalize returns n as an Int if it fits, or as a BigInt otherwise.
+func normalize(n *big.Int) Value {
+	if n.IsInt64() {
+		return Int(n.Int64())
+	}
+	return BigInt{n}
+}
+
+// normalizeRat returns r as an integer if it is one, or as a Rat otherwise.
+func normalizeRat(r *big.Rat) Value {
+	if r.IsInt() {
+		return normalize(new(big.Int).Set(r.Num()))
+	}
+	return Rat{r}
+}
+
+func toBig(v Value) *big.Int {
+	switch num := v.(type) {
+	case Int:
+		return big.NewInt(int64(num))
+	case BigInt:
+		return num.Int
+	}
+	panic("not an integer")
+}
+
+func toRat(v Value) *big.Rat {
+	switch num := v.(type) {
+	case Int, BigInt:
+		return new(big.Rat).SetInt(toBig(num))
+	case Rat:
+		return num.Rat
+	}
+	panic("not an exact number")
+}
+
+func toFloat(v Value) Float {
+	switch num := v.(type) {
+	case Int:
+		return Float(num)
+	case BigInt:
+		f, _ := new(big.Float).SetInt(num.Int).Float64()
+		return Float(f)
+	case Rat:
+		f, _ := num.Float64()
+		return Float(f)
+	case Float:
+		return num
+	}
+	panic("not a number")
+}
+
+// arith is a binary operation on numbers, defined for each level of the
+// numeric tower. The operation on Ints may fail with errIntOverflow, and is
+// then carried out on BigInts.
+type arith struct {
+	ints   func(a, b Int) (Value, error)
+	bigs   func(a, b *big.Int) (Value, error)
+	rats   func(a, b *big.Rat) (Value, error)
+	floats func(a, b Float) (Value, error)
+}
+
+func (op arith) apply(a, b Value) (Value, error) {
+	la, _ := numberLevel(a)
+	lb, _ := numberLevel(b)
+	if la < lb {
+		la = lb
+	}
+
+	switch la {
+	case intLevel:
+		result, err := op.ints(a.(Int), b.(Int))
+		if err != errIntOverflow {
+			return result, err
+		}
+		fallthrough
+	case bigLevel:
+		return op.bigs(toBig(a), toBig(b))
+	case ratLevel:
+		return op.rats(toRat(a), toRat(b))
+	default:
+		return op.floats(toFloat(a), toFloat(b))
+	}
+}
+
+// fold applies op from left to right over the numbers.
+func (op arith) fold(init Value, nums []Value) (Value, error) {
+	result := init
+	for _, num := range nums {
+		var err error
+		if result, err = op.apply(result, num); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+var (
+	arithAdd = arith{
+		ints: func(a, b Int) (Value, error) {
+			c := a + b
+			if (a > 0 && b > 0 && c < 0) || (a < 0 && b < 0 && c >= 0) {
+				return nil, errIntOverflow
+			}
+			return c, nil
+		},
+		bigs: func(a, b *big.Int) (Value, error) {
+			return normalize(new(big.Int).Add(a, b)), nil
+		},
+		rats: func(a, b *big.Rat) (Value, error) {
+			return normalizeRat(new(big.Rat).Add(a, b)), nil
+		},
+		floats: func(a, b Float) (Value, error) { return a + b, nil },
+	}
+	arithSub = arith{
+		ints: func(a, b Int) (Value, error) {
+			c := a - b
+			if (a >= 0 && b < 0 && c < 0) || (a < 0 && b > 0 && c >= 0) {
+				return nil, errIntOverflow
+			}
+			return c, nil
+		},
+		bigs: func(a, b *big.Int) (Value, error) {
+			return normalize(new(big.Int).Sub(a, b)), nil
+		},
+		rats: func(a, b *big.Rat) (Value, error) {
+			return normalizeRat(new(big.Rat).Sub(a, b)), nil
+		},
+		floats: func(a, b Float) (Value, error) { return a - b, nil },
+	}
+	arithMul = arith{
+		ints: func(a, b Int) (Value, error) {
+			if a == 0 || b == 0 {
+				return Int(0), nil
+			}
+			c := a * b
+			if c/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+				return nil, errIntOverflow
+			}
+			return c, nil
+		},
+		bigs: func(a, b *big.Int) (Value, error) {
+			return normalize(new(big.Int).Mul(a, b)), nil
+		},
+		rats: func(a, b *big.Rat) (Value, error) {
+			return normalizeRat(new(big.Rat).Mul(a, b)), nil
+		},
+		floats: func(a, b Float) (Value, error) { return a * b, nil },
+	}
+	// the quotient of integers is a rational if it is not an integer
+	arithDiv = arith{
+		ints: func(a, b Int) (Value, error) {
+			if b == 0 {
+				return nil, errDivisionByZero
+			}
+			if a == math.MinInt64 && b == -1 {
+				return nil, errIntOverflow
+			}
+			if a%b == 0 {
+				return a / b, nil
+			}
+			return Rat{big.NewRat(int64(a), int64(b))}, nil
+		},
+		bigs: func(a, b *big.Int) (Value, error) {
+			if b.Sign() == 0 {
+				return nil, errDivisionByZero
+			}
+			return normalizeRat(new(big.Rat).SetFrac(a, b)), nil
+		},
+		rats: func(a, b *big.Rat) (Value, error) {
+			if b.Sign() == 0 {
+				return nil, errDivisionByZero
+			}
+			return normalizeRat(new(big.Rat).Quo(a, b)), nil
+		},
+		floats: func(a, b Float) (Value, error) { return a / b, nil },
+	}
+	// remainder of the truncated division, which has the sign of the dividend
+	arithMod = arith{
+		ints: func(a, b Int) (Value, error) {
+			if b == 0 {
+				return nil, errDivisionByZero
+			}
+			if b == -1 {
+				return Int(0), nil
+			}
+			return a % b, nil
+		},
+		bigs: func(a, b *big.Int) (Value, error) {
+			if b.Sign() == 0 {
+				return nil, errDivisionByZero
+			}
+			return normalize(new(big.Int).Rem(a, b)), nil
+		},
+		rats:   func(a, b *big.Rat) (Value, error) { return nil, errTypeMismatch },
+		floats: func(a, b Float) (Value, error) { return nil, errTypeMismatch },
+	}
+)
+
+// compare returns -1, 0 or 1 as a is less than, equal to or greater than b.
+// It reports false if the numbers are not ordered, i.e. one of them is NaN.
+func compare(a, b Value) (int, bool) {
+	la, _ := numberLevel(a)
+	lb, _ := numberLevel(b)
+	if la < lb {
+		la = lb
+	}
+
+	switch la {
+	case intLevel:
+		x, y := a.(Int), b.(Int)
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+		return 0, true
+	case bigLevel:
+		return toBig(a).Cmp(toBig(b)), true
+	case ratLevel:
+		return toRat(a).Cmp(toRat(b)), true
+	default:
+		// compare a float with an exact number exactly: an infinite float is
+		// beyond every exact number, however large
+		if isExact(a) {
+			return compareExact(a, b.(Float))
+		}
+		if isExact(b) {
+			cmp, ok := compareExact(b, a.(Float))
+			return -cmp, ok
+		}
+		x, y := a.(Float), b.(Float)
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		case x == y:
+			return 0, true
+		}
+		return 0, false
+	}
+}
+
+// compareExact compares the exact number x with the float y.
+func compareExact(x Value, y Float) (int, bool) {
+	switch {
+	case math.IsNaN(float64(y)):
+		return 0, false
+	case math.IsInf(float64(y), 1):
+		return -1, true
+	case math.IsInf(float64(y), -1):
+		return 1, true
+	}
+	return toRat(x).Cmp(floatToRat(y)), true
+}
+
+// compareChain reports whether every two adjacent numbers satisfy pred.
+func compareChain(args []Value, pred func(int) bool) (Value, error) {
+	if len(args) == 0 {
+		return nil, errArityMismatch
+	}
+	if err := checkNumbers(args); err != nil {
+		return nil, err
+	}
+
+	for i := 1; i < len(args); i++ {
+		cmp, ok := compare(args[i-1], args[i])
+		if !ok || !pred(cmp) {
+			return Bool(false), nil
+		}
+	}
+	return Bool(true), nil
+}
+
+func isFinite(f Float) bool {
+	return !math.IsInf(float64(f), 0) && !math.IsNaN(float64(f))
+}
+
+func floatToRat(f Float) *big.Rat {
+	return new(big.Rat).SetFloat64(float64(f))
+}
+
+// toExact converts a float to the exact number of the same value.
+func toExact(f Float) (Value, error) {
+	if !isFinite(f) {
+		return nil, errNoExact
+	}
+	return normalizeRat(floatToRat(f)), nil
+}
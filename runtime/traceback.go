@@ -0,0 +1,51 @@
+package runtime
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TraceError is an error raised inside a procedure call, along with the
+// procedure calls on the stack when it was raised.
+type TraceError struct {
+	Err    error
+	frames []stackframe
+}
+
+func (e *TraceError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TraceError) Unwrap() error {
+	return e.Err
+}
+
+// Traceback describes the procedure calls on the stack, one line per call
+// with the most recent call last. Frames replaced by tail calls are noted.
+func (e *TraceError) Traceback() []string {
+	var lines []string
+	for _, frame := range e.frames {
+		if frame.elided == 1 {
+			lines = append(lines, "... 1 frame elided by tail calls")
+		} else if frame.elided > 1 {
+			lines = append(lines, fmt.Sprintf("... %d frames elided by tail calls", frame.elided))
+		}
+		lines = append(lines, frame.String())
+	}
+	return lines
+}
+
+func (f stackframe) String() string {
+	substr := []string{f.proc.String()}
+	if f.proc.name != nil {
+		substr[0] = *f.proc.name
+	}
+	for _, param := range f.params {
+		substr = append(substr, fmt.Sprintf("%s", param))
+	}
+	call := "(" + strings.Join(substr, " ") + ")"
+	if f.site == nil {
+		return call
+	}
+	return fmt.Sprintf("%s: %s", f.site, call)
+}
@@ -6,12 +6,44 @@ const (
 	NIL = iota
 	BOOLEAN
 	INTEGER
+	BIGINT
+	RATIONAL
+	FLOAT
+	STRING
+	CHAR
 	SYMBOL
 	PAIR
+	VECTOR
+	BYTEVECTOR
+	HASH_TABLE
 	BUILTIN_PROC
 	PROC
 )
 
+var (
+	kindNames = map[Kind]string{
+		NIL:          "nil",
+		BOOLEAN:      "boolean",
+		INTEGER:      "integer",
+		BIGINT:       "bigint",
+		RATIONAL:     "rational",
+		FLOAT:        "float",
+		STRING:       "string",
+		CHAR:         "char",
+		SYMBOL:       "symbol",
+		PAIR:         "pair",
+		VECTOR:       "vector",
+		BYTEVECTOR:   "bytevector",
+		HASH_TABLE:   "hash-table",
+		BUILTIN_PROC: "builtin",
+		PROC:         "procedure",
+	}
+)
+
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
 type Type struct {
 	kind Kind
 }
@@ -24,8 +56,16 @@ var (
 	TypeNil         = Type{kind: NIL}
 	TypeBool        = Type{kind: BOOLEAN}
 	TypeInt         = Type{kind: INTEGER}
+	TypeBigInt      = Type{kind: BIGINT}
+	TypeRat         = Type{kind: RATIONAL}
+	TypeFloat       = Type{kind: FLOAT}
+	TypeString      = Type{kind: STRING}
+	TypeChar        = Type{kind: CHAR}
 	TypeSymbol      = Type{kind: SYMBOL}
 	TypePair        = Type{kind: PAIR}
+	TypeVector      = Type{kind: VECTOR}
+	TypeBytevector  = Type{kind: BYTEVECTOR}
+	TypeHashTable   = Type{kind: HASH_TABLE}
 	TypeBuiltinProc = Type{kind: BUILTIN_PROC}
 	TypeProc        = Type{kind: PROC}
 )
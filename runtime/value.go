@@ -2,8 +2,11 @@ package runtime
 
 import (
 	"fmt"
+	"math"
+	"math/big"
 	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/dyzsr/mylisp/ast"
 )
@@ -23,6 +26,23 @@ type (
 
 	Int int64
 
+	// BigInt is an integer out of the range of Int. It must not be modified.
+	BigInt struct {
+		*big.Int
+	}
+
+	// Rat is an exact rational number which is not an integer. It must not be
+	// modified.
+	Rat struct {
+		*big.Rat
+	}
+
+	Float float64
+
+	String string
+
+	Char rune
+
 	Symbol struct {
 		*string
 	}
@@ -32,27 +52,108 @@ type (
 		second Value
 	}
 
+	Vector struct {
+		items []Value
+	}
+
+	Bytevector struct {
+		bytes []byte
+	}
+
+	// HashTable maps keys to values, comparing the keys with eqv? or equal?.
+	HashTable struct {
+		eqv     bool                  // compare keys with eqv? rather than equal?
+		buckets map[interface{}][]int // entry indexes by key or by hash of key
+		entries []hashEntry           // in insertion order
+		count   int
+	}
+
 	BuiltinProc struct {
 		name string
 		proc func(...Value) (Value, error)
-		typ  Type
+		// call replaces proc for the built-ins which call procedures
+		call  func(*Runtime, ...Value) (Value, error)
+		arity arity
 	}
 
 	Proc struct {
 		name  *string
 		outer *ast.Scope
 		*ast.LambdaExpr
-		typ Type
+		// cases are the clauses of a case-lambda, whose first one is also
+		// the embedded LambdaExpr
+		cases []*ast.LambdaExpr
 	}
 )
 
+// arity is the range of the numbers of arguments a procedure takes. max is -1
+// if there is no upper bound.
+type arity struct {
+	min, max int
+}
+
+func fixed(n int) arity          { return arity{n, n} }
+func between(min, max int) arity { return arity{min, max} }
+func atLeast(n int) arity        { return arity{n, -1} }
+
+func (a arity) accepts(n int) bool {
+	return n >= a.min && (a.max < 0 || n <= a.max)
+}
+
+func lambdaArity(lambda *ast.LambdaExpr) arity {
+	if lambda.Rest != nil {
+		return atLeast(len(lambda.Args))
+	}
+	return between(len(lambda.Args), len(lambda.Args)+len(lambda.Optional))
+}
+
+// arity of a case-lambda spans those of all its clauses.
+func (v *Proc) arity() arity {
+	result := lambdaArity(v.LambdaExpr)
+	for _, lambda := range v.cases {
+		a := lambdaArity(lambda)
+		if a.min < result.min {
+			result.min = a.min
+		}
+		if result.max >= 0 && (a.max < 0 || a.max > result.max) {
+			result.max = a.max
+		}
+	}
+	return result
+}
+
+// match returns the clause of a procedure which takes n arguments, or nil if
+// there is none.
+func (v *Proc) match(n int) *ast.LambdaExpr {
+	if v.cases == nil {
+		if lambdaArity(v.LambdaExpr).accepts(n) {
+			return v.LambdaExpr
+		}
+		return nil
+	}
+	for _, lambda := range v.cases {
+		if lambdaArity(lambda).accepts(n) {
+			return lambda
+		}
+	}
+	return nil
+}
+
 func (Nil) Type() Type            { return TypeNil }
 func (Bool) Type() Type           { return TypeBool }
 func (Int) Type() Type            { return TypeInt }
+func (BigInt) Type() Type         { return TypeBigInt }
+func (Rat) Type() Type            { return TypeRat }
+func (Float) Type() Type          { return TypeFloat }
+func (String) Type() Type         { return TypeString }
+func (Char) Type() Type           { return TypeChar }
 func (v Symbol) Type() Type       { return TypeSymbol }
 func (v *Pair) Type() Type        { return TypePair }
-func (v *BuiltinProc) Type() Type { return v.typ }
-func (v *Proc) Type() Type        { return v.typ }
+func (v *Vector) Type() Type      { return TypeVector }
+func (v *Bytevector) Type() Type  { return TypeBytevector }
+func (v *HashTable) Type() Type   { return TypeHashTable }
+func (v *BuiltinProc) Type() Type { return TypeBuiltinProc }
+func (v *Proc) Type() Type        { return TypeProc }
 
 func (Nil) String() string {
 	return "nil"
@@ -66,26 +167,101 @@ func (v Int) String() string {
 	return strconv.FormatInt(int64(v), 10)
 }
 
+func (v Float) String() string {
+	f := float64(v)
+	switch {
+	case math.IsInf(f, 1):
+		return "+inf.0"
+	case math.IsInf(f, -1):
+		return "-inf.0"
+	case math.IsNaN(f):
+		return "+nan.0"
+	}
+	str := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(str, ".e") {
+		str += ".0"
+	}
+	return str
+}
+
+// String returns the string literal which reads back as v.
+func (v String) String() string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, ch := range string(v) {
+		switch ch {
+		case '"', '\\':
+			sb.WriteRune('\\')
+			sb.WriteRune(ch)
+		case '\a':
+			sb.WriteString(`\a`)
+		case '\b':
+			sb.WriteString(`\b`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		default:
+			if unicode.IsPrint(ch) {
+				sb.WriteRune(ch)
+			} else {
+				fmt.Fprintf(&sb, `\x%x;`, ch)
+			}
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+var (
+	charNames = map[Char]string{
+		'\a':   "alarm",
+		'\b':   "backspace",
+		'\x7f': "delete",
+		'\x1b': "escape",
+		'\n':   "newline",
+		0:      "null",
+		'\r':   "return",
+		' ':    "space",
+		'\t':   "tab",
+	}
+)
+
+// String returns the character literal which reads back as v.
+func (v Char) String() string {
+	if name, ok := charNames[v]; ok {
+		return `#\` + name
+	}
+	if !unicode.IsPrint(rune(v)) {
+		return fmt.Sprintf(`#\x%x`, rune(v))
+	}
+	return `#\` + string(v)
+}
+
 func (v Symbol) String() string {
 	return *v.string
 }
 
 func (v *Pair) String() string {
+	return write(v)
+}
+
+func (v *Vector) String() string {
+	return write(v)
+}
+
+func (v *Bytevector) String() string {
 	var substr []string
-	p := v
-	for {
-		substr = append(substr, fmt.Sprintf("%s", p.first))
-		if _, ok := p.second.(Nil); ok {
-			break
-		}
-		u, ok := p.second.(*Pair)
-		if !ok {
-			substr = append(substr, ".", fmt.Sprintf("%s", p.second))
-			break
-		}
-		p = u
+	for _, b := range v.bytes {
+		substr = append(substr, strconv.Itoa(int(b)))
 	}
-	return "(" + strings.Join(substr, " ") + ")"
+	return "#u8(" + strings.Join(substr, " ") + ")"
+}
+
+func (v *HashTable) String() string {
+	return "<hash-table>"
 }
 
 func (v *BuiltinProc) String() string {
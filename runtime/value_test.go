@@ -2,6 +2,7 @@ package runtime
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"testing"
 )
@@ -25,6 +26,50 @@ func Test_ValueFormat(t *testing.T) {
 			input:  Int(-1),
 			result: "-1",
 		},
+		{
+			input:  String("a \"quoted\"\tstring\\\n"),
+			result: `"a \"quoted\"\tstring\\\n"`,
+		},
+		{
+			input:  String("\x00"),
+			result: `"\x0;"`,
+		},
+		{
+			input:  bigInt("-123456789012345678901234567890"),
+			result: "-123456789012345678901234567890",
+		},
+		{
+			input:  rat(-1, 3),
+			result: "-1/3",
+		},
+		{
+			input:  Float(3),
+			result: "3.0",
+		},
+		{
+			input:  Float(-0.125),
+			result: "-0.125",
+		},
+		{
+			input:  Float(1e21),
+			result: "1e+21",
+		},
+		{
+			input:  Float(math.Inf(-1)),
+			result: "-inf.0",
+		},
+		{
+			input:  Char('a'),
+			result: `#\a`,
+		},
+		{
+			input:  Char(' '),
+			result: `#\space`,
+		},
+		{
+			input:  Char('\x01'),
+			result: `#\x1`,
+		},
 		{
 			input:  &Symbol{&symbols[0]},
 			result: "abc",
@@ -58,3 +103,37 @@ func Test_ValueFormat(t *testing.T) {
 		}
 	}
 }
+
+func Test_ValueFormatCycle(t *testing.T) {
+	cycle := &Pair{first: Int(1), second: &Pair{first: Int(2), second: Nil{}}}
+	cycle.second.(*Pair).second = cycle
+
+	tail := &Pair{first: Int(2), second: Nil{}}
+	tail.second = tail
+
+	shared := &Pair{first: Int(1), second: Nil{}}
+
+	vec := &Vector{items: []Value{Int(1), nil}}
+	vec.items[1] = vec
+
+	nested := &Pair{first: Nil{}, second: Nil{}}
+	nested.first = nested
+
+	testData := []struct {
+		input  Value
+		result string
+	}{
+		{input: cycle, result: "#0=(1 2 . #0#)"},
+		{input: &Pair{first: Int(1), second: tail}, result: "(1 . #0=(2 . #0#))"},
+		{input: &Pair{first: shared, second: &Pair{first: shared, second: Nil{}}}, result: "((1) (1))"},
+		{input: vec, result: "#0=#(1 #0#)"},
+		{input: &Vector{items: []Value{cycle, vec}}, result: "#(#0=(1 2 . #0#) #1=#(1 #1#))"},
+		{input: nested, result: "#0=(#0#)"},
+	}
+	for _, test := range testData {
+		result := fmt.Sprintf("%s", test.input)
+		if result != test.result {
+			t.Errorf("\nexpect: '%s'\noutput: '%s'", test.result, result)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package runtime
+
+import (
+	"fmt"
+	"strings"
+)
+
+// writer prints values. The pairs and vectors on a cycle are printed with
+// datum labels, as in #0=(1 . #0#).
+type writer struct {
+	sb     strings.Builder
+	labels map[Value]int // label of each value on a cycle, -1 until assigned
+	next   int
+}
+
+func write(v Value) string {
+	w := &writer{labels: make(map[Value]int)}
+	w.findCycles(v, make(map[Value]bool))
+	w.write(v)
+	return w.sb.String()
+}
+
+// findCycles marks the values reachable from v which refer back to
+// themselves. visited maps the values being visited to true, and the values
+// completely visited to false.
+func (w *writer) findCycles(v Value, visited map[Value]bool) {
+	switch v.(type) {
+	case *Pair, *Vector:
+	default:
+		return
+	}
+	if onPath, ok := visited[v]; ok {
+		if onPath {
+			w.labels[v] = -1
+		}
+		return
+	}
+
+	visited[v] = true
+	switch v := v.(type) {
+	case *Pair:
+		w.findCycles(v.first, visited)
+		w.findCycles(v.second, visited)
+	case *Vector:
+		for _, item := range v.items {
+			w.findCycles(item, visited)
+		}
+	}
+	visited[v] = false
+}
+
+func (w *writer) write(v Value) {
+	if w.label(v) {
+		return
+	}
+	switch v := v.(type) {
+	case *Pair:
+		w.sb.WriteByte('(')
+		w.write(v.first)
+		rest := v.second
+		for {
+			if _, ok := rest.(Nil); ok {
+				break
+			}
+			p, ok := rest.(*Pair)
+			if _, labeled := w.labels[rest]; !ok || labeled {
+				w.sb.WriteString(" . ")
+				w.write(rest)
+				break
+			}
+			w.sb.WriteByte(' ')
+			w.write(p.first)
+			rest = p.second
+		}
+		w.sb.WriteByte(')')
+	case *Vector:
+		w.sb.WriteString("#(")
+		for i, item := range v.items {
+			if i > 0 {
+				w.sb.WriteByte(' ')
+			}
+			w.write(item)
+		}
+		w.sb.WriteByte(')')
+	default:
+		fmt.Fprintf(&w.sb, "%s", v)
+	}
+}
+
+// label prints the label of a value on a cycle: #n= before the value is
+// printed for the first time, and #n# in place of the value afterwards. It
+// reports whether the value has been printed already.
+func (w *writer) label(v Value) bool {
+	n, ok := w.labels[v]
+	if !ok {
+		return false
+	}
+	if n >= 0 {
+		fmt.Fprintf(&w.sb, "#%d#", n)
+		return true
+	}
+	w.labels[v] = w.next
+	fmt.Fprintf(&w.sb, "#%d=", w.next)
+	w.next++
+	return false
+}
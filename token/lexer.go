@@ -1,8 +1,11 @@
 package token
 
 import (
+	"fmt"
 	"io"
+	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/dyzsr/mylisp/ast"
 )
@@ -13,11 +16,18 @@ type Lexer struct {
 	eof  bool
 	tok  *Token
 	node ast.Expr
+	span ast.Span // source range of tok
+	err  error    // reason of an illegal tok
 }
 
 func NewLexer(reader io.Reader) *Lexer {
+	return NewFileLexer("", reader)
+}
+
+// NewFileLexer returns a lexer whose positions refer to the named file.
+func NewFileLexer(filename string, reader io.Reader) *Lexer {
 	return &Lexer{
-		sc: newScanner(reader),
+		sc: newScanner(filename, reader),
 	}
 }
 
@@ -50,72 +60,232 @@ func (l *Lexer) advance() bool {
 		return false
 	}
 
-	l.skipWhitespace()
-	if !l.sc.notEof() {
-		l.eof = true
-		return false
+	var tok Token
+	for {
+		l.skipWhitespace()
+		if !l.sc.notEof() {
+			l.eof = true
+			return false
+		}
+
+		from := l.sc.pos()
+		ch, _ := l.sc.get()
+		if ch == ';' {
+			l.skipLineComment()
+			continue
+		}
+		if ch == '#' {
+			if next, _ := l.sc.peek(); next == '|' {
+				l.sc.get()
+				if !l.skipBlockComment() {
+					to := l.sc.pos()
+					l.node = nil
+					l.span = ast.Span{From: &from, To: &to}
+					tok = l.illegal("unterminated block comment")
+					break
+				}
+				continue
+			}
+		}
+		l.node, l.err = nil, nil
+		tok = l.read(ch)
+		to := l.sc.lastPos()
+		to.Column++
+		l.span = ast.Span{From: &from, To: &to}
+		ast.SetSpan(l.node, l.span.From, l.span.To)
+		break
 	}
+	l.tok = &tok
+	return true
+}
 
+func (l *Lexer) read(ch rune) Token {
 	var tok Token
-	switch ch, _ := l.sc.get(); ch {
+	switch ch {
 	case '(':
 		tok = LPAREN
 	case ')':
 		tok = RPAREN
+	case '#':
+		tok = l.readSharp()
+	case '"':
+		tok = l.readString()
+	case '\'':
+		tok = QUOTE
+		l.node = ast.NewIdent("'")
+	case '`':
+		tok = QUASIQUOTE
+		l.node = ast.NewIdent("`")
+	case ',':
+		tok = UNQUOTE
+		if next, _ := l.sc.peek(); next == '@' {
+			l.sc.get()
+			tok = UNQUOTE_SPLICING
+		}
+		l.node = ast.NewIdent(tok.String())
 	default:
-		if unicode.IsNumber(ch) {
-			tok = l.readNumber(ch)
-		} else if unicode.IsLetter(ch) || ch == '_' {
-			tok = l.readIdent(ch)
+		if unicode.IsControl(ch) {
+			tok = l.illegal("unexpected character %q", ch)
 		} else {
-			tok = l.readOther(ch)
+			tok = l.readAtom(ch)
 		}
 	}
-	l.tok = &tok
-	return true
+	return tok
 }
 
-func (l *Lexer) readNumber(first rune) Token {
-	var sign bool
-	var value int64
+// readSharp reads the syntax following a '#'.
+func (l *Lexer) readSharp() Token {
+	ch, ok := l.sc.peek()
+	if !ok {
+		return l.illegal("unexpected EOF after '#'")
+	}
+	switch ch {
+	case ';':
+		l.sc.get()
+		return DATUM_COMMENT
+	case '\\':
+		l.sc.get()
+		return l.readChar()
+	case '(':
+		l.sc.get()
+		return VECTOR
+	case 'u', 'U':
+		return l.readBytevector()
+	case '!':
+		return l.readDirective()
+	}
+	if strings.ContainsRune("xXoObBdDeEiI", ch) {
+		return l.readNumber(l.readDelimited("#"))
+	}
+	return l.illegal("bad syntax '%s'", l.readDelimited("#"))
+}
 
-	switch first {
-	case '+':
-	case '-':
-		sign = true
-	default:
-		value = int64(first - '0')
+// readDirective reads a '#!optional' or a '#!rest', which mark the optional
+// and the rest parameters of a lambda. They are read as identifiers.
+func (l *Lexer) readDirective() Token {
+	text := l.readDelimited("#")
+	switch text {
+	case "#!optional", "#!rest":
+		l.node = ast.NewIdent(text)
+		return IDENT
+	}
+	return l.illegal("bad syntax '%s'", text)
+}
+
+// readBytevector reads the '#u8(' which starts a bytevector.
+func (l *Lexer) readBytevector() Token {
+	prefix := "#"
+	for _, want := range "u8(" {
+		ch, ok := l.sc.peek()
+		if !ok || unicode.ToLower(ch) != want {
+			return l.illegal("bad syntax '%s'", l.readDelimited(prefix))
+		}
+		l.sc.get()
+		prefix += string(ch)
 	}
+	return BYTEVECTOR
+}
 
-	for ; l.sc.notEof(); l.sc.get() {
-		ch, _ := l.sc.peek()
-		if !unicode.IsNumber(ch) {
-			break
+var (
+	charNames = map[string]rune{
+		"alarm":     '\a',
+		"backspace": '\b',
+		"delete":    '\x7f',
+		"escape":    '\x1b',
+		"newline":   '\n',
+		"null":      0,
+		"return":    '\r',
+		"space":     ' ',
+		"tab":       '\t',
+	}
+)
+
+// readChar reads a character literal after its leading '#\\'.
+func (l *Lexer) readChar() Token {
+	first, ok := l.sc.get()
+	if !ok {
+		return l.illegal("unexpected EOF after '#\\'")
+	}
+	name := []rune{first}
+	if isIdentSubsequent(first) {
+		for ; l.sc.notEof(); l.sc.get() {
+			ch, _ := l.sc.peek()
+			if !isIdentSubsequent(ch) {
+				break
+			}
+			name = append(name, ch)
 		}
-		value = value*10 + int64(ch-'0')
 	}
-	if sign {
-		value = -value
+
+	var value rune
+	if len(name) == 1 {
+		value = first
+	} else if named, ok := charNames[string(name)]; ok {
+		value = named
+	} else if first == 'x' || first == 'X' {
+		for _, ch := range name[1:] {
+			digit, ok := hexDigit(ch)
+			if !ok || value > unicode.MaxRune {
+				return l.illegal("bad character '#\\%s'", string(name))
+			}
+			value = value*16 + digit
+		}
+		if !utf8.ValidRune(value) {
+			return l.illegal("bad character '#\\%s'", string(name))
+		}
+	} else {
+		return l.illegal("bad character '#\\%s'", string(name))
 	}
-	l.node = &ast.IntLit{Value: value}
-	return INTEGER
+	l.node = &ast.CharLit{Value: value}
+	return CHAR
+}
+
+// readAtom reads a number or an identifier, which extends up to the next
+// delimiter.
+func (l *Lexer) readAtom(first rune) Token {
+	text := l.readDelimited(string(first))
+	if looksLikeNumber([]rune(text)) {
+		return l.readNumber(text)
+	}
+	return l.readIdent(text)
 }
 
-func (l *Lexer) readIdent(first rune) Token {
-	value := []rune{first}
+// readDelimited reads the text following prefix up to the next delimiter.
+func (l *Lexer) readDelimited(prefix string) string {
+	value := []rune(prefix)
 	for ; l.sc.notEof(); l.sc.get() {
 		ch, _ := l.sc.peek()
-		if !(unicode.IsNumber(ch) || unicode.IsLetter(ch) || ch == '_') {
-			if ch == '?' || ch == '!' {
-				value = append(value, ch)
-				l.sc.get()
-			}
+		if isDelimiter(ch) {
 			break
 		}
 		value = append(value, ch)
 	}
+	return string(value)
+}
+
+func (l *Lexer) readNumber(text string) Token {
+	expr, err := ParseNumber(text, 10)
+	if err != nil {
+		return l.illegal("%s '%s'", err, text)
+	}
+	l.node = expr
+	switch expr.(type) {
+	case *ast.RatLit:
+		return RATIONAL
+	case *ast.FloatLit:
+		return FLOAT
+	}
+	return INTEGER
+}
+
+func (l *Lexer) readIdent(ident string) Token {
+	for i, ch := range ident {
+		if i == 0 && !(isIdentInitial(ch) || ch == '+' || ch == '-' || ch == '.') ||
+			i > 0 && !isIdentSubsequent(ch) {
+			return l.illegal("bad identifier '%s'", ident)
+		}
+	}
 
-	ident := string(value)
 	tok := l.lookup(ident)
 	switch tok {
 	case TRUE:
@@ -128,79 +298,132 @@ func (l *Lexer) readIdent(first rune) Token {
 	return tok
 }
 
-func (l *Lexer) readOther(first rune) Token {
-	switch first {
-	case '+', '-':
-		ch, _ := l.sc.peek()
-		if unicode.IsNumber(ch) {
-			return l.readNumber(first)
+// readString reads a string literal after its opening '"'. A bad escape
+// sequence makes the whole literal illegal.
+func (l *Lexer) readString() Token {
+	var value []rune
+	var bad bool
+	for {
+		ch, ok := l.sc.get()
+		if !ok {
+			return l.illegal("unterminated string")
+		}
+		switch ch {
+		case '"':
+			if bad {
+				return ILLEGAL
+			}
+			l.node = &ast.StringLit{Value: string(value)}
+			return STRING
+		case '\\':
+			if bad {
+				l.sc.get()
+				continue
+			}
+			escaped, ok := l.readEscape()
+			if !ok {
+				bad = true
+			} else if escaped >= 0 {
+				value = append(value, escaped)
+			}
+		default:
+			value = append(value, ch)
 		}
 	}
+}
 
-	ch, ok := l.sc.peek()
+// readEscape reads an escape sequence after a '\\' in a string literal. It
+// returns -1 for a line continuation, which stands for no character.
+func (l *Lexer) readEscape() (rune, bool) {
+	ch, ok := l.sc.get()
+	if !ok {
+		l.illegal("unterminated string")
+		return 0, false
+	}
+	switch ch {
+	case 'a':
+		return '\a', true
+	case 'b':
+		return '\b', true
+	case 't':
+		return '\t', true
+	case 'n':
+		return '\n', true
+	case 'r':
+		return '\r', true
+	case '"', '\\', '|':
+		return ch, true
+	case 'x', 'X':
+		var value rune
+		for digits := 0; ; digits++ {
+			ch, ok := l.sc.get()
+			if ch == ';' && digits > 0 && utf8.ValidRune(value) {
+				return value, true
+			}
+			digit, isHex := hexDigit(ch)
+			if !ok || !isHex || value > unicode.MaxRune {
+				l.illegal("bad hex escape in string")
+				return 0, false
+			}
+			value = value*16 + digit
+		}
+	}
 
-	var tok Token
-	switch first {
-	case '\'':
-		tok = QUOTE
-		l.node = ast.NewIdent("'")
-	case '+':
-		tok = PLUS
-		l.node = ast.NewIdent("+")
-	case '-':
-		tok = MINUS
-		l.node = ast.NewIdent("-")
-	case '*':
-		tok = ASTER
-		l.node = ast.NewIdent("*")
-	case '/':
-		tok = SLASH
-		l.node = ast.NewIdent("/")
-	case '=':
-		tok = EQ
-		l.node = ast.NewIdent("=")
-	case '<':
-		if !ok {
-			tok = ILLEGAL
-		} else if ch != '=' {
-			tok = LT
-			l.node = ast.NewIdent("<")
-		} else {
-			tok = LTE
-			l.node = ast.NewIdent("<=")
-			l.sc.get()
+	// line continuation: \<intraline whitespace>*<newline><intraline whitespace>*
+	for ch == ' ' || ch == '\t' {
+		if ch, ok = l.sc.get(); !ok {
+			l.illegal("unterminated string")
+			return 0, false
 		}
-	case '>':
-		if !ok {
-			tok = ILLEGAL
-		} else if ch != '=' {
-			tok = GT
-			l.node = ast.NewIdent(">")
-		} else {
-			tok = GTE
-			l.node = ast.NewIdent(">=")
-			l.sc.get()
+	}
+	if ch != '\n' {
+		l.illegal("bad escape '\\%c' in string", ch)
+		return 0, false
+	}
+	for l.sc.notEof() {
+		if next, _ := l.sc.peek(); next != ' ' && next != '\t' {
+			break
 		}
+		l.sc.get()
 	}
+	return -1, true
+}
 
-	return tok
+// illegal records the reason of an illegal token.
+func (l *Lexer) illegal(format string, args ...interface{}) Token {
+	l.err = fmt.Errorf(format, args...)
+	return ILLEGAL
 }
 
 func (l *Lexer) lookup(ident string) Token {
 	switch ident {
+	case ".":
+		return DOT
 	case "true":
 		return TRUE
 	case "false":
 		return FALSE
+	case "+":
+		return PLUS
+	case "-":
+		return MINUS
+	case "*":
+		return ASTER
+	case "/":
+		return SLASH
+	case "=":
+		return EQ
+	case "<":
+		return LT
+	case "<=":
+		return LTE
+	case ">":
+		return GT
+	case ">=":
+		return GTE
 	case "mod":
 		l.node = ast.NewIdent("mod")
 		return MOD
-	case "and":
-		l.node = ast.NewIdent("and")
-		return AND
-	case "or":
-		l.node = ast.NewIdent("or")
-		return OR
 	case "not":
 		l.node = ast.NewIdent("not")
 		return NOT
@@ -213,6 +436,21 @@ func (l *Lexer) Node() ast.Expr {
 	return l.node
 }
 
+// Err returns the reason why the current token is illegal.
+func (l *Lexer) Err() error {
+	return l.err
+}
+
+// Line returns the text of a source line read so far.
+func (l *Lexer) Line(line int) (string, bool) {
+	return l.sc.lineText(line)
+}
+
+// Span returns the source range of the current token.
+func (l *Lexer) Span() ast.Span {
+	return l.span
+}
+
 func (l *Lexer) skipWhitespace() {
 	for l.sc.notEof() {
 		ch, _ := l.sc.peek()
@@ -222,3 +460,84 @@ func (l *Lexer) skipWhitespace() {
 		l.sc.get()
 	}
 }
+
+// skipLineComment skips the rest of the line after a ';'.
+func (l *Lexer) skipLineComment() {
+	for l.sc.notEof() {
+		if ch, _ := l.sc.get(); ch == '\n' {
+			return
+		}
+	}
+}
+
+// skipBlockComment skips a possibly nested block comment after its opening
+// '#|'. It reports false if EOF is reached before the comment is closed.
+func (l *Lexer) skipBlockComment() bool {
+	depth := 1
+	for l.sc.notEof() {
+		ch, _ := l.sc.get()
+		next, _ := l.sc.peek()
+		switch {
+		case ch == '#' && next == '|':
+			l.sc.get()
+			depth++
+		case ch == '|' && next == '#':
+			l.sc.get()
+			depth--
+			if depth == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isIdentInitial(ch rune) bool {
+	return unicode.IsLetter(ch) || strings.ContainsRune("!$%&*/:<=>?^_~", ch)
+}
+
+func isIdentSubsequent(ch rune) bool {
+	return isIdentInitial(ch) || unicode.IsNumber(ch) || strings.ContainsRune("+-.@", ch)
+}
+
+func hexDigit(ch rune) (rune, bool) {
+	switch {
+	case '0' <= ch && ch <= '9':
+		return ch - '0', true
+	case 'a' <= ch && ch <= 'f':
+		return ch - 'a' + 10, true
+	case 'A' <= ch && ch <= 'F':
+		return ch - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// isDelimiter reports whether ch ends a number or an identifier.
+func isDelimiter(ch rune) bool {
+	return unicode.IsSpace(ch) || strings.ContainsRune("()\";'`,", ch)
+}
+
+// looksLikeNumber reports whether an atom is meant to be a number: it starts
+// with a digit, or with a sign or a decimal point followed by a digit.
+func looksLikeNumber(atom []rune) bool {
+	if _, ok := specialFloats[string(atom)]; ok {
+		return true
+	}
+	for i, ch := range atom {
+		switch {
+		case isDigit(ch, 10):
+			return true
+		case ch == '+' || ch == '-':
+			if i > 0 {
+				return false
+			}
+		case ch == '.':
+			if i > 1 || i == 1 && atom[0] == '.' {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return false
+}
@@ -1,9 +1,12 @@
 package token
 
 import (
+	"math/big"
 	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/dyzsr/mylisp/ast"
 )
 
 type testLexer struct {
@@ -35,8 +38,8 @@ var (
 		{
 			input: "(and (= x y) (not (< y 1)) (or (<= z 9) (> a b c) (>= d e f)))",
 			result: []Token{
-				LPAREN, AND, LPAREN, EQ, IDENT, IDENT, RPAREN, LPAREN, NOT, LPAREN, LT, IDENT, INTEGER, RPAREN, RPAREN,
-				LPAREN, OR, LPAREN, LTE, IDENT, INTEGER, RPAREN, LPAREN, GT, IDENT, IDENT, IDENT, RPAREN,
+				LPAREN, IDENT, LPAREN, EQ, IDENT, IDENT, RPAREN, LPAREN, NOT, LPAREN, LT, IDENT, INTEGER, RPAREN, RPAREN,
+				LPAREN, IDENT, LPAREN, LTE, IDENT, INTEGER, RPAREN, LPAREN, GT, IDENT, IDENT, IDENT, RPAREN,
 				LPAREN, GTE, IDENT, IDENT, IDENT, RPAREN, RPAREN, RPAREN,
 			},
 		},
@@ -47,6 +50,56 @@ var (
 				QUOTE, LPAREN, INTEGER, INTEGER, QUOTE, INTEGER, RPAREN, RPAREN,
 			},
 		},
+		{
+			input:  "; line comment\n(define x ; trailing\n 1) #| block #| nested |# |# x",
+			result: []Token{LPAREN, IDENT, IDENT, INTEGER, RPAREN, IDENT},
+		},
+		{
+			input:  "(f #;(g x) y) #;z",
+			result: []Token{LPAREN, IDENT, DATUM_COMMENT, LPAREN, IDENT, IDENT, RPAREN, IDENT, RPAREN, DATUM_COMMENT, IDENT},
+		},
+		{
+			input: `(string-append "a" "b\"c") (string->symbol "x") (<=? a->b *x* -y +)`,
+			result: []Token{
+				LPAREN, IDENT, STRING, STRING, RPAREN, LPAREN, IDENT, STRING, RPAREN,
+				LPAREN, IDENT, IDENT, IDENT, IDENT, PLUS, RPAREN,
+			},
+		},
+		{
+			input:  "(- -1 +.5 ... -> .5x)",
+			result: []Token{LPAREN, MINUS, INTEGER, FLOAT, IDENT, IDENT, ILLEGAL, RPAREN},
+		},
+		{
+			input: "`(a ,b ,@(c) ,'d x,y)",
+			result: []Token{
+				QUASIQUOTE, LPAREN, IDENT, UNQUOTE, IDENT, UNQUOTE_SPLICING, LPAREN, IDENT, RPAREN,
+				UNQUOTE, QUOTE, IDENT, IDENT, UNQUOTE, IDENT, RPAREN,
+			},
+		},
+		{
+			input:  "(a . b) (1 .2 . .x)",
+			result: []Token{LPAREN, IDENT, DOT, IDENT, RPAREN, LPAREN, INTEGER, FLOAT, DOT, IDENT, RPAREN},
+		},
+		{
+			input:  "(#x1F #e1.5 #b102 #q 1)",
+			result: []Token{LPAREN, INTEGER, RATIONAL, ILLEGAL, ILLEGAL, INTEGER, RPAREN},
+		},
+		{
+			input:  "#(1 #(a) '#())",
+			result: []Token{VECTOR, INTEGER, VECTOR, IDENT, RPAREN, QUOTE, VECTOR, RPAREN, RPAREN},
+		},
+		{
+			input:  "(#u8(1 2) #U8() #u9 #u)",
+			result: []Token{LPAREN, BYTEVECTOR, INTEGER, INTEGER, RPAREN, BYTEVECTOR, RPAREN, ILLEGAL, ILLEGAL, RPAREN},
+		},
+		{
+			input:  "(a #!optional b #!rest c #!eof)",
+			result: []Token{LPAREN, IDENT, IDENT, IDENT, IDENT, IDENT, ILLEGAL, RPAREN},
+		},
+		{
+			input:  "x #| unterminated",
+			result: []Token{IDENT, ILLEGAL},
+		},
 	}
 )
 
@@ -73,3 +126,159 @@ func testNextToken(input string) []Token {
 	println()
 	return result
 }
+
+func TestReadString(t *testing.T) {
+	testData := []struct {
+		input  string
+		result string
+		ok     bool
+	}{
+		{input: `"abc"`, result: "abc", ok: true},
+		{input: `""`, result: "", ok: true},
+		{input: `"a\"b\\c\|d"`, result: `a"b\c|d`, ok: true},
+		{input: `"\a\b\t\n\r"`, result: "\a\b\t\n\r", ok: true},
+		{input: `"\x41;\x3bb;"`, result: "A\u03bb", ok: true},
+		{input: "\"line \\  \n   continued\"", result: "line continued", ok: true},
+		{input: "\"multi\nline\"", result: "multi\nline", ok: true},
+		{input: `"\q"`, ok: false},
+		{input: `"\x41"`, ok: false},
+		{input: `"\x110000;"`, ok: false},
+		{input: `"abc`, ok: false},
+	}
+
+	for _, test := range testData {
+		l := NewLexer(strings.NewReader(test.input))
+		tok, node := l.Next()
+		if !test.ok {
+			if tok != ILLEGAL || l.Err() == nil {
+				t.Errorf("\ninput: '%s'\nexpect an illegal token\noutput: %s %v", test.input, tok, node)
+			}
+			continue
+		}
+		lit, ok := node.(*ast.StringLit)
+		if tok != STRING || !ok || lit.Value != test.result {
+			t.Errorf("\ninput: '%s'\nexpect: %q\noutput: %s %v", test.input, test.result, tok, node)
+		}
+	}
+}
+
+func TestReadChar(t *testing.T) {
+	testData := []struct {
+		input  string
+		result rune
+		ok     bool
+	}{
+		{input: `#\a`, result: 'a', ok: true},
+		{input: `#\A)`, result: 'A', ok: true},
+		{input: `#\(`, result: '(', ok: true},
+		{input: `#\ `, result: ' ', ok: true},
+		{input: `#\λ`, result: 'λ', ok: true},
+		{input: `#\space`, result: ' ', ok: true},
+		{input: `#\newline`, result: '\n', ok: true},
+		{input: `#\x`, result: 'x', ok: true},
+		{input: `#\x41`, result: 'A', ok: true},
+		{input: `#\abc`, ok: false},
+		{input: `#\xZZ`, ok: false},
+		{input: `#\xd800`, ok: false},
+	}
+
+	for _, test := range testData {
+		l := NewLexer(strings.NewReader(test.input))
+		tok, node := l.Next()
+		if !test.ok {
+			if tok != ILLEGAL || l.Err() == nil {
+				t.Errorf("\ninput: '%s'\nexpect an illegal token\noutput: %s %v", test.input, tok, node)
+			}
+			continue
+		}
+		lit, ok := node.(*ast.CharLit)
+		if tok != CHAR || !ok || lit.Value != test.result {
+			t.Errorf("\ninput: '%s'\nexpect: %q\noutput: %s %v", test.input, test.result, tok, node)
+		}
+	}
+}
+
+func TestReadNumber(t *testing.T) {
+	testData := []struct {
+		input  string
+		result ast.Expr
+	}{
+		{input: "123", result: &ast.IntLit{Value: 123}},
+		{input: "-123", result: &ast.IntLit{Value: -123}},
+		{input: "+7", result: &ast.IntLit{Value: 7}},
+		{input: "3.14", result: &ast.FloatLit{Value: 3.14}},
+		{input: "1e-9", result: &ast.FloatLit{Value: 1e-9}},
+		{input: "-2.5E3", result: &ast.FloatLit{Value: -2500}},
+		{input: ".5", result: &ast.FloatLit{Value: .5}},
+		{input: "-.5", result: &ast.FloatLit{Value: -.5}},
+		{input: "1.", result: &ast.FloatLit{Value: 1}},
+		{input: "12abc", result: nil},
+		{input: "1.2.3", result: nil},
+		{input: "1e", result: nil},
+		{input: "-99999999999999999999", result: &ast.BigIntLit{Value: bigInt("-99999999999999999999")}},
+		{input: "1e999", result: nil},
+		{input: "3/4", result: &ast.RatLit{Value: big.NewRat(3, 4)}},
+		{input: "-6/4", result: &ast.RatLit{Value: big.NewRat(-3, 2)}},
+		{input: "4/2", result: &ast.IntLit{Value: 2}},
+		{input: "1/0", result: nil},
+		{input: "1/-2", result: nil},
+		{input: "1.5/2", result: nil},
+		{input: "#x1F", result: &ast.IntLit{Value: 31}},
+		{input: "#XfF", result: &ast.IntLit{Value: 255}},
+		{input: "#o17", result: &ast.IntLit{Value: 15}},
+		{input: "#b-1010", result: &ast.IntLit{Value: -10}},
+		{input: "#d99", result: &ast.IntLit{Value: 99}},
+		{input: "#x1/A", result: &ast.RatLit{Value: big.NewRat(1, 10)}},
+		{input: "#xFFFFFFFFFFFFFFFFF", result: &ast.BigIntLit{Value: bigInt("295147905179352825855")}},
+		{input: "#e1.25", result: &ast.RatLit{Value: big.NewRat(5, 4)}},
+		{input: "#e0.1", result: &ast.RatLit{Value: big.NewRat(1, 10)}},
+		{input: "#e1e3", result: &ast.IntLit{Value: 1000}},
+		{input: "#x#e10", result: &ast.IntLit{Value: 16}},
+		{input: "#i#x10", result: &ast.FloatLit{Value: 16}},
+		{input: "#i1/4", result: &ast.FloatLit{Value: .25}},
+		{input: "#x1G", result: nil},
+		{input: "#b102", result: nil},
+		{input: "#o8", result: nil},
+		{input: "#x1.5", result: nil},
+		{input: "#x#x1", result: nil},
+		{input: "#e#i1", result: nil},
+		{input: "#e+inf.0", result: nil},
+		{input: "#b", result: nil},
+		{input: "١٢", result: nil},
+	}
+
+	for _, test := range testData {
+		l := NewLexer(strings.NewReader(test.input))
+		tok, node := l.Next()
+		if test.result == nil {
+			if tok != ILLEGAL || l.Err() == nil {
+				t.Errorf("\ninput: '%s'\nexpect an illegal token\noutput: %s %v", test.input, tok, node)
+			}
+			continue
+		}
+		ast.SetSpan(node, nil, nil)
+		if !reflect.DeepEqual(node, test.result) {
+			t.Errorf("\ninput: '%s'\nexpect: %v\noutput: %s %v", test.input, test.result, tok, node)
+		}
+	}
+}
+
+func TestNumberErrorSpan(t *testing.T) {
+	l := NewLexer(strings.NewReader("(f\n  #b102)"))
+	for tok, _ := l.Next(); tok != ILLEGAL; tok, _ = l.Next() {
+		if tok == EOF {
+			t.Fatal("expect an illegal token")
+		}
+	}
+	if err := l.Err(); err == nil || err.Error() != "bad number '#b102'" {
+		t.Errorf("expect: bad number '#b102', output: %v", err)
+	}
+	if span := l.Span(); *span.From != ast.NewPos(2, 3) || *span.To != ast.NewPos(2, 8) {
+		t.Errorf("expect: 2:3-2:8, output: %v-%v", span.From, span.To)
+	}
+}
+
+func bigInt(s string) *big.Int {
+	n, _ := new(big.Int).SetString(s, 10)
+	return n
+}
@@ -0,0 +1,217 @@
+package token
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/dyzsr/mylisp/ast"
+)
+
+var (
+	errBadNumber      = errors.New("bad number")
+	errNumberOverflow = errors.New("number out of range")
+)
+
+var (
+	radixes = map[byte]int{'x': 16, 'o': 8, 'b': 2, 'd': 10}
+
+	specialFloats = map[string]float64{
+		"+inf.0": math.Inf(1),
+		"-inf.0": math.Inf(-1),
+		"+nan.0": math.NaN(),
+		"-nan.0": math.NaN(),
+	}
+)
+
+// ParseNumber parses the textual form of a number in the given radix into an
+// IntLit, a BigIntLit, a RatLit or a FloatLit. The text may start with the
+// prefixes #x, #o, #b and #d, which override the radix, and #e and #i, which
+// make the number exact or inexact. Decimal points and exponents are only
+// allowed in radix 10.
+func ParseNumber(text string, radix int) (ast.Expr, error) {
+	var exactness byte
+	var hasRadix bool
+	for len(text) >= 2 && text[0] == '#' {
+		switch prefix := text[1] | 0x20; prefix {
+		case 'x', 'o', 'b', 'd':
+			if hasRadix {
+				return nil, errBadNumber
+			}
+			hasRadix = true
+			radix = radixes[prefix]
+		case 'e', 'i':
+			if exactness != 0 {
+				return nil, errBadNumber
+			}
+			exactness = prefix
+		default:
+			return nil, errBadNumber
+		}
+		text = text[2:]
+	}
+
+	expr, err := parseNumber(text, radix)
+	if err != nil {
+		return nil, err
+	}
+	switch exactness {
+	case 'e':
+		return toExact(expr, text)
+	case 'i':
+		return toInexact(expr), nil
+	}
+	return expr, nil
+}
+
+func parseNumber(text string, radix int) (ast.Expr, error) {
+	if value, ok := specialFloats[text]; ok {
+		return &ast.FloatLit{Value: value}, nil
+	}
+	if i := strings.IndexByte(text, '/'); i >= 0 {
+		return parseRational(text[:i], text[i+1:], radix)
+	}
+
+	digits, isFloat := scanNumber(text, radix)
+	if digits == 0 {
+		return nil, errBadNumber
+	}
+
+	if isFloat {
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, errNumberOverflow
+		}
+		return &ast.FloatLit{Value: value}, nil
+	}
+	if value, err := strconv.ParseInt(text, radix, 64); err == nil {
+		return &ast.IntLit{Value: value}, nil
+	}
+	// too large for an int64
+	value, ok := new(big.Int).SetString(text, radix)
+	if !ok {
+		return nil, errBadNumber
+	}
+	return &ast.BigIntLit{Value: value}, nil
+}
+
+// parseRational parses a rational number written as numerator/denominator,
+// which is reduced to an integer if possible.
+func parseRational(numer, denom string, radix int) (ast.Expr, error) {
+	if digits, isFloat := scanNumber(numer, radix); digits == 0 || isFloat {
+		return nil, errBadNumber
+	}
+	if digits, isFloat := scanNumber(denom, radix); digits == 0 || isFloat || !isDigit(rune(denom[0]), radix) {
+		return nil, errBadNumber
+	}
+
+	n, _ := new(big.Int).SetString(numer, radix)
+	d, _ := new(big.Int).SetString(denom, radix)
+	if d.Sign() == 0 {
+		return nil, errBadNumber
+	}
+	return ratLit(new(big.Rat).SetFrac(n, d)), nil
+}
+
+// ratLit makes the literal of an exact number, which is an integer literal if
+// the number is integral.
+func ratLit(value *big.Rat) ast.Expr {
+	if !value.IsInt() {
+		return &ast.RatLit{Value: value}
+	}
+	if num := value.Num(); num.IsInt64() {
+		return &ast.IntLit{Value: num.Int64()}
+	}
+	return &ast.BigIntLit{Value: value.Num()}
+}
+
+// toExact converts a number literal to an exact one. A decimal number is
+// converted from its text, so that #e0.1 is exactly 1/10.
+func toExact(expr ast.Expr, text string) (ast.Expr, error) {
+	lit, ok := expr.(*ast.FloatLit)
+	if !ok {
+		return expr, nil
+	}
+	if _, special := specialFloats[text]; special {
+		return nil, errBadNumber
+	}
+	value, ok := new(big.Rat).SetString(text)
+	if !ok {
+		value = new(big.Rat).SetFloat64(lit.Value)
+	}
+	return ratLit(value), nil
+}
+
+// toInexact converts a number literal to a floating-point literal.
+func toInexact(expr ast.Expr) ast.Expr {
+	switch lit := expr.(type) {
+	case *ast.IntLit:
+		return &ast.FloatLit{Value: float64(lit.Value)}
+	case *ast.BigIntLit:
+		value, _ := new(big.Float).SetInt(lit.Value).Float64()
+		return &ast.FloatLit{Value: value}
+	case *ast.RatLit:
+		value, _ := lit.Value.Float64()
+		return &ast.FloatLit{Value: value}
+	}
+	return expr
+}
+
+// scanNumber checks that text is made of an optional sign, digits with an
+// optional decimal point and an optional exponent. It returns the number of
+// digits before the exponent, which is zero if text is malformed.
+func scanNumber(text string, radix int) (digits int, isFloat bool) {
+	i := 0
+	if i < len(text) && (text[i] == '+' || text[i] == '-') {
+		i++
+	}
+	for ; i < len(text); i++ {
+		if ch := text[i]; ch == '.' && radix == 10 && !isFloat {
+			isFloat = true
+		} else if isDigit(rune(ch), radix) {
+			digits++
+		} else {
+			break
+		}
+	}
+	if digits == 0 {
+		return 0, false
+	}
+
+	if i < len(text) && radix == 10 && (text[i] == 'e' || text[i] == 'E') {
+		isFloat = true
+		i++
+		if i < len(text) && (text[i] == '+' || text[i] == '-') {
+			i++
+		}
+		start := i
+		for i < len(text) && isDigit(rune(text[i]), 10) {
+			i++
+		}
+		if i == start {
+			return 0, false
+		}
+	}
+	if i != len(text) {
+		return 0, false
+	}
+	return digits, isFloat
+}
+
+// isDigit reports whether ch is an ASCII digit in the given radix.
+func isDigit(ch rune, radix int) bool {
+	var value rune
+	switch {
+	case '0' <= ch && ch <= '9':
+		value = ch - '0'
+	case 'a' <= ch && ch <= 'z':
+		value = ch - 'a' + 10
+	case 'A' <= ch && ch <= 'Z':
+		value = ch - 'A' + 10
+	default:
+		return false
+	}
+	return value < rune(radix)
+}
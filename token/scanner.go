@@ -3,10 +3,14 @@ package token
 import (
 	"bufio"
 	"io"
+
+	"github.com/dyzsr/mylisp/ast"
 )
 
 type scanner struct {
-	rd *bufio.Scanner
+	rd       *bufio.Scanner
+	filename string
+	lines    []string // lines read so far
 
 	buf    []rune
 	offset int
@@ -14,13 +18,14 @@ type scanner struct {
 	eof    bool // EOF encountered?
 	char   rune // current character
 
-	line int
+	line int     // line of the current character
+	last ast.Pos // position of the last character returned by get
 }
 
-func newScanner(reader io.Reader) *scanner {
+func newScanner(filename string, reader io.Reader) *scanner {
 	sc := &scanner{
-		rd:   bufio.NewScanner(reader),
-		line: 1,
+		rd:       bufio.NewScanner(reader),
+		filename: filename,
 	}
 	sc.load()
 	return sc
@@ -36,6 +41,7 @@ func (sc *scanner) get() (rune, bool) {
 		return 0, false
 	}
 	char := sc.char
+	sc.last = sc.pos()
 	sc.offset++
 	if sc.offset >= sc.size { // reaches the end of buf
 		sc.load()
@@ -55,6 +61,31 @@ func (sc *scanner) peek() (rune, bool) {
 	return sc.char, true
 }
 
+// pos returns the position of the current character.
+func (sc *scanner) pos() ast.Pos {
+	if sc.eof {
+		pos := sc.last
+		pos.Column++
+		return pos
+	}
+	pos := ast.NewPos(sc.line, sc.offset+1)
+	pos.Filename = sc.filename
+	return pos
+}
+
+// lastPos returns the position of the last character returned by get.
+func (sc *scanner) lastPos() ast.Pos {
+	return sc.last
+}
+
+// lineText returns the text of a line read so far, without its line break.
+func (sc *scanner) lineText(line int) (string, bool) {
+	if line < 1 || line > len(sc.lines) {
+		return "", false
+	}
+	return sc.lines[line-1], true
+}
+
 func (sc *scanner) load() {
 	// fmt.Println("load: start")
 	// defer fmt.Println("load: end")
@@ -75,6 +106,7 @@ func (sc *scanner) load() {
 	}
 	// not EOF
 	sc.line++
+	sc.lines = append(sc.lines, sc.rd.Text())
 	sc.buf = append([]rune(sc.rd.Text()), '\n')
 	sc.size = len(sc.buf)
 	sc.offset = 0
@@ -1,8 +1,11 @@
 package token
 
 import (
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/dyzsr/mylisp/ast"
 )
 
 type testScanner struct {
@@ -47,7 +50,7 @@ var (
 
 func testGetPeek(input string) string {
 	r := strings.NewReader(input)
-	sc := newScanner(r)
+	sc := newScanner("", r)
 
 	var result []rune
 	for sc.notEof() {
@@ -60,7 +63,7 @@ func testGetPeek(input string) string {
 
 func testPeek(input string) string {
 	r := strings.NewReader(input)
-	sc := newScanner(r)
+	sc := newScanner("", r)
 
 	var result []rune
 	for sc.notEof() {
@@ -73,7 +76,7 @@ func testPeek(input string) string {
 
 func testGet(input string) string {
 	r := strings.NewReader(input)
-	sc := newScanner(r)
+	sc := newScanner("", r)
 
 	var result []rune
 	for sc.notEof() {
@@ -82,3 +85,24 @@ func testGet(input string) string {
 	}
 	return string(result)
 }
+
+func TestPos(t *testing.T) {
+	sc := newScanner("", strings.NewReader("ab\n\ncd"))
+
+	var result []ast.Pos
+	for sc.notEof() {
+		result = append(result, sc.pos())
+		sc.get()
+	}
+	expect := []ast.Pos{
+		ast.NewPos(1, 1), ast.NewPos(1, 2), ast.NewPos(1, 3),
+		ast.NewPos(2, 1),
+		ast.NewPos(3, 1), ast.NewPos(3, 2), ast.NewPos(3, 3),
+	}
+	if !reflect.DeepEqual(result, expect) {
+		t.Errorf("\nexpect: %v\noutput: %v", expect, result)
+	}
+	if pos := sc.pos(); pos != ast.NewPos(3, 4) {
+		t.Errorf("EOF position: expect: %v, output: %v", ast.NewPos(3, 4), pos)
+	}
+}
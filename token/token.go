@@ -10,11 +10,23 @@ const (
 	TRUE
 	FALSE
 	INTEGER
+	RATIONAL
+	FLOAT
+	STRING
+	CHAR
 
 	LPAREN
 	RPAREN
 
+	VECTOR
+	BYTEVECTOR
+
+	DOT
 	QUOTE
+	QUASIQUOTE
+	UNQUOTE
+	UNQUOTE_SPLICING
+	DATUM_COMMENT
 	PLUS
 	MINUS
 	ASTER
@@ -25,35 +37,42 @@ const (
 	LTE
 	GT
 	GTE
-	AND
-	OR
 	NOT
 )
 
 var (
 	tokenString = map[Token]string{
-		ILLEGAL: "<illegal>",
-		EOF:     "<eof>",
-		IDENT:   "id",
-		TRUE:    "true",
-		FALSE:   "false",
-		INTEGER: "int",
-		LPAREN:  "(",
-		RPAREN:  ")",
-		QUOTE:   "`",
-		PLUS:    "+",
-		MINUS:   "-",
-		ASTER:   "*",
-		SLASH:   "/",
-		MOD:     "%",
-		EQ:      "=",
-		LT:      "<",
-		LTE:     "<=",
-		GT:      ">",
-		GTE:     ">=",
-		AND:     "&&",
-		OR:      "||",
-		NOT:     "!",
+		ILLEGAL:          "<illegal>",
+		EOF:              "<eof>",
+		IDENT:            "id",
+		TRUE:             "true",
+		FALSE:            "false",
+		INTEGER:          "int",
+		RATIONAL:         "rational",
+		FLOAT:            "float",
+		STRING:           "string",
+		CHAR:             "char",
+		LPAREN:           "(",
+		RPAREN:           ")",
+		VECTOR:           "#(",
+		BYTEVECTOR:       "#u8(",
+		DOT:              ".",
+		QUOTE:            "'",
+		QUASIQUOTE:       "`",
+		UNQUOTE:          ",",
+		UNQUOTE_SPLICING: ",@",
+		DATUM_COMMENT:    "#;",
+		PLUS:             "+",
+		MINUS:            "-",
+		ASTER:            "*",
+		SLASH:            "/",
+		MOD:              "%",
+		EQ:               "=",
+		LT:               "<",
+		LTE:              "<=",
+		GT:               ">",
+		GTE:              ">=",
+		NOT:              "!",
 	}
 )
 